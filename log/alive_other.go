@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package log
+
+import "net"
+
+func alive(conn net.Conn) bool {
+	return true
+}
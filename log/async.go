@@ -0,0 +1,224 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+type DropPolicy uint8
+
+const (
+	// DropPolicyBlock makes Write wait for space in the queue.
+	DropPolicyBlock DropPolicy = iota
+	// DropPolicyNewest discards the message being written.
+	DropPolicyNewest
+	// DropPolicyOldest discards the oldest queued message.
+	DropPolicyOldest
+	// DropPolicyBelowSeverity discards messages less severe than AsyncWriter.DropSeverity and blocks for the others.
+	DropPolicyBelowSeverity
+)
+
+var (
+	ErrWriterClosed   = errors.New("log: writer closed")
+	ErrMessageDropped = errors.New("log: message dropped")
+)
+
+var closedChannel = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// AsyncWriter queues Messages and writes them to another Writer in the background.
+type AsyncWriter struct {
+	DropSeverity Severity
+	// ErrorHandler, if set, is called from the background goroutine for each failed write.
+	ErrorHandler func(error)
+
+	writer  Writer
+	policy  DropPolicy
+	queue   chan *Message
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	// mu guards closed and the registration of senders, never a channel send,
+	// so that Close is not held up by a Write blocked on a full queue.
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	senders sync.WaitGroup
+
+	state   sync.Mutex
+	pending int
+	idle    chan struct{}
+	err     error
+}
+
+// NewAsyncWriter queues up to size Messages. A size below 1 is raised to 1, since
+// the drop policies need a buffer to drop from.
+func NewAsyncWriter(w Writer, size int, policy DropPolicy) *AsyncWriter {
+	if size < 1 {
+		size = 1
+	}
+	a := &AsyncWriter{
+		writer:  w,
+		policy:  policy,
+		queue:   make(chan *Message, size),
+		done:    make(chan struct{}),
+		closing: make(chan struct{}),
+		idle:    closedChannel,
+	}
+	go a.run()
+	return a
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	for msg := range w.queue {
+		if err := w.writer.Write(msg); err != nil {
+			w.state.Lock()
+			w.err = err
+			w.state.Unlock()
+			if w.ErrorHandler != nil {
+				w.ErrorHandler(err)
+			}
+		}
+		w.finish()
+	}
+}
+
+func (w *AsyncWriter) begin() {
+	w.state.Lock()
+	defer w.state.Unlock()
+
+	if w.pending == 0 {
+		w.idle = make(chan struct{})
+	}
+	w.pending++
+}
+
+func (w *AsyncWriter) finish() {
+	w.state.Lock()
+	defer w.state.Unlock()
+
+	w.pending--
+	if w.pending == 0 {
+		close(w.idle)
+	}
+}
+
+func (w *AsyncWriter) drop() {
+	w.dropped.Add(1)
+	w.finish()
+}
+
+func (w *AsyncWriter) Write(msg *Message) error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return ErrWriterClosed
+	}
+	w.senders.Add(1)
+	w.mu.RUnlock()
+	defer w.senders.Done()
+
+	w.begin()
+	select {
+	case w.queue <- msg:
+		return nil
+	default:
+	}
+
+	switch w.policy {
+	case DropPolicyNewest:
+		w.drop()
+		return ErrMessageDropped
+	case DropPolicyOldest:
+		for {
+			select {
+			case <-w.queue:
+				w.drop()
+			default:
+			}
+			select {
+			case w.queue <- msg:
+				return nil
+			default:
+			}
+		}
+	case DropPolicyBelowSeverity:
+		if msg.Header.Priority.Severity > w.DropSeverity {
+			w.drop()
+			return ErrMessageDropped
+		}
+	}
+
+	select {
+	case w.queue <- msg:
+		return nil
+	case <-w.closing:
+		w.finish()
+		return ErrWriterClosed
+	}
+}
+
+// Dropped returns the number of messages discarded because the queue was full.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush waits until every queued message has been written, flushes the
+// underlying Writer and returns the last write error since the previous Flush.
+func (w *AsyncWriter) Flush(ctx context.Context) error {
+	w.state.Lock()
+	idle := w.idle
+	w.state.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return errors.Join(w.takeErr(), FlushWriter(ctx, w.writer))
+}
+
+func (w *AsyncWriter) takeErr() error {
+	w.state.Lock()
+	defer w.state.Unlock()
+
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Close stops accepting messages, waits until the queue is drained and closes the underlying Writer.
+// Writes blocked on a full queue return ErrWriterClosed.
+func (w *AsyncWriter) Close(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.closing)
+		// the queue is closed once no Write can send to it anymore.
+		go func() {
+			w.senders.Wait()
+			close(w.queue)
+		}()
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return errors.Join(w.takeErr(), CloseWriter(ctx, w.writer))
+}
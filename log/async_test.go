@@ -0,0 +1,266 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter blocks every Write until the gate is opened.
+type gateWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	msgs []string
+	err  error
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(msg *Message) error {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.msgs = append(w.msgs, msg.text())
+	return w.err
+}
+
+func (w *gateWriter) written() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.msgs...)
+}
+
+func newSeverityMessage(severity Severity, msg string) *Message {
+	m := newTestMessage(msg)
+	m.Header.Priority.Severity = severity
+	return m
+}
+
+func TestAsyncWriter_policy(t *testing.T) {
+	type test struct {
+		name        string
+		policy      DropPolicy
+		messages    []*Message
+		want        []string
+		wantDropped uint64
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newGateWriter()
+			a := NewAsyncWriter(w, 2, tt.policy)
+			a.DropSeverity = SeverityWarning
+
+			// the first message is taken by the background goroutine and blocks there.
+			a.Write(newTestMessage("first"))
+			deadline := time.Now().Add(time.Second)
+			for len(a.queue) != 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			for _, msg := range tt.messages {
+				a.Write(msg)
+			}
+			close(w.gate)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := a.Close(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			got := w.written()
+			if len(tt.want) != len(got) {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+			for i := range got {
+				if tt.want[i] != got[i] {
+					t.Fatalf("want=%v, got=%v.", tt.want, got)
+				}
+			}
+			if tt.wantDropped != a.Dropped() {
+				t.Fatalf("want=%v, got=%v.", tt.wantDropped, a.Dropped())
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:   "drop newest",
+			policy: DropPolicyNewest,
+			messages: []*Message{
+				newTestMessage("a"),
+				newTestMessage("b"),
+				newTestMessage("c"),
+			},
+			want:        []string{"first", "a", "b"},
+			wantDropped: 1,
+		},
+		{
+			name:   "drop oldest",
+			policy: DropPolicyOldest,
+			messages: []*Message{
+				newTestMessage("a"),
+				newTestMessage("b"),
+				newTestMessage("c"),
+				newTestMessage("d"),
+			},
+			want:        []string{"first", "c", "d"},
+			wantDropped: 2,
+		},
+		{
+			name:   "drop below severity",
+			policy: DropPolicyBelowSeverity,
+			messages: []*Message{
+				newSeverityMessage(SeverityDebug, "a"),
+				newSeverityMessage(SeverityDebug, "b"),
+				newSeverityMessage(SeverityDebug, "c"),
+			},
+			want:        []string{"first", "a", "b"},
+			wantDropped: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestAsyncWriter_block(t *testing.T) {
+	w := newGateWriter()
+	a := NewAsyncWriter(w, 1, DropPolicyBlock)
+
+	written := make(chan struct{})
+	go func() {
+		for _, msg := range []string{"a", "b", "c"} {
+			a.Write(newTestMessage(msg))
+		}
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("want Write to block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(w.gate)
+	<-written
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := a.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 3, len(w.written()); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+	if want, got := uint64(0), a.Dropped(); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func TestAsyncWriter_Flush(t *testing.T) {
+	w := newGateWriter()
+	w.err = errors.New("relay down")
+	a := NewAsyncWriter(w, 8, DropPolicyBlock)
+
+	var handled []error
+	a.ErrorHandler = func(err error) { handled = append(handled, err) }
+	a.Write(newTestMessage("a"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := a.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want=%v, got=%v.", context.DeadlineExceeded, err)
+	}
+
+	close(w.gate)
+	if err := a.Flush(context.Background()); !errors.Is(err, w.err) {
+		t.Fatalf("want=%v, got=%v.", w.err, err)
+	}
+	if len(handled) != 1 {
+		t.Fatalf("want=%v, got=%v.", 1, len(handled))
+	}
+	if err := a.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsyncWriter_Close(t *testing.T) {
+	w := newGateWriter()
+	close(w.gate)
+	a := NewAsyncWriter(w, 8, DropPolicyBlock)
+
+	a.Write(newTestMessage("a"))
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Write(newTestMessage("b")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("want=%v, got=%v.", ErrWriterClosed, err)
+	}
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, len(w.written()); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func TestAsyncWriter_Close_blocked(t *testing.T) {
+	w := newGateWriter()
+	defer close(w.gate)
+	a := NewAsyncWriter(w, 1, DropPolicyBlock)
+
+	// "a" is stuck in the downstream Writer, "b" fills the queue and "c" blocks.
+	a.Write(newTestMessage("a"))
+	deadline := time.Now().Add(time.Second)
+	for len(a.queue) != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	a.Write(newTestMessage("b"))
+	blocked := make(chan error)
+	go func() {
+		blocked <- a.Write(newTestMessage("c"))
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := a.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want=%v, got=%v.", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("want=%v, got=%v.", "Close to honour the deadline", elapsed)
+	}
+
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, ErrWriterClosed) {
+			t.Fatalf("want=%v, got=%v.", ErrWriterClosed, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("want the blocked Write to return after Close")
+	}
+	if err := a.Write(newTestMessage("d")); !errors.Is(err, ErrWriterClosed) {
+		t.Fatalf("want=%v, got=%v.", ErrWriterClosed, err)
+	}
+}
+
+func TestNewAsyncWriter_size(t *testing.T) {
+	for _, size := range []int{-1, 0} {
+		w := newGateWriter()
+		a := NewAsyncWriter(w, size, DropPolicyOldest)
+		if want, got := 1, cap(a.queue); want != got {
+			t.Fatalf("want=%v, got=%v.", want, got)
+		}
+		close(w.gate)
+		if err := a.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorFaint = "\x1b[2m"
+	colorBold  = "\x1b[1m"
+)
+
+var severityColors = [...]string{
+	SeverityEmergency:     "\x1b[1;41;37m",
+	SeverityAlert:         "\x1b[1;41;37m",
+	SeverityCritical:      "\x1b[1;31m",
+	SeverityError:         "\x1b[31m",
+	SeverityWarning:       "\x1b[33m",
+	SeverityNotice:        "\x1b[36m",
+	SeverityInformational: "\x1b[32m",
+	SeverityDebug:         "\x1b[90m",
+}
+
+var severityLabels = [...]string{
+	SeverityEmergency:     "EMERG",
+	SeverityAlert:         "ALERT",
+	SeverityCritical:      "CRIT ",
+	SeverityError:         "ERROR",
+	SeverityWarning:       "WARN ",
+	SeverityNotice:        "NOTE ",
+	SeverityInformational: "INFO ",
+	SeverityDebug:         "DEBUG",
+}
+
+// ConsoleFormatter renders Messages for humans reading a terminal.
+type ConsoleFormatter struct {
+	Color bool
+	// TimeFormat defaults to "15:04:05.000".
+	TimeFormat string
+}
+
+func (f ConsoleFormatter) paint(color, s string) string {
+	if !f.Color {
+		return s
+	}
+	return color + s + colorReset
+}
+
+func (f ConsoleFormatter) Format(msg *Message) ([]byte, error) {
+	h := msg.Header
+	parts := make([]string, 0, 5)
+
+	if h.Timestamp.Valid {
+		layout := f.TimeFormat
+		if layout == "" {
+			layout = "15:04:05.000"
+		}
+		parts = append(parts, f.paint(colorFaint, time.Time(h.Timestamp.Value).Format(layout)))
+	}
+
+	severity := h.Priority.Severity
+	if int(severity) < len(severityLabels) {
+		parts = append(parts, f.paint(severityColors[severity], severityLabels[severity]))
+	} else {
+		parts = append(parts, severity.String())
+	}
+
+	app := ""
+	if h.App.Valid {
+		app = h.App.Value.String()
+		if i := strings.LastIndexAny(app, `/\`); i >= 0 {
+			app = app[i+1:]
+		}
+	}
+	if h.ProcessID.Valid {
+		app += "[" + h.ProcessID.Value.String() + "]"
+	}
+	if h.MessageID.Valid {
+		if app != "" {
+			app += " "
+		}
+		app += h.MessageID.Value.String()
+	}
+	if app != "" {
+		parts = append(parts, f.paint(colorBold, app+":"))
+	}
+
+	if text := msg.text(); text != "" {
+		parts = append(parts, text)
+	}
+
+	for _, meta := range msg.Metadata {
+		if len(meta.Params) == 0 {
+			parts = append(parts, f.paint(colorFaint, "["+meta.ID.String()+"]"))
+		}
+		for _, param := range meta.Params {
+			parts = append(parts, f.paint(colorFaint, meta.ID.String()+"."+param.Name.String()+"=")+logfmtValue(param.Value.String()))
+		}
+	}
+
+	return []byte(strings.Join(parts, " ")), nil
+}
+
+// defaultFormatter picks ConsoleFormatter when stdout is a terminal and RFC 5424 otherwise.
+// Colors are disabled by the NO_COLOR environment variable.
+func defaultFormatter() Formatter {
+	if !isTerminal(os.Stdout) {
+		return RFC5424Formatter{}
+	}
+	return ConsoleFormatter{
+		Color: os.Getenv("NO_COLOR") == "",
+	}
+}
@@ -0,0 +1,105 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConsoleFormatter_Format(t *testing.T) {
+	type test struct {
+		name      string
+		formatter ConsoleFormatter
+		message   *Message
+		want      string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != string(got) {
+				t.Fatalf("want=%q, got=%q.", tt.want, got)
+			}
+		})
+	}
+
+	message := NewMessage(
+		NewHeader(
+			NewPriority(FacilityUserLevelMessages, SeverityError),
+			1,
+			option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 789000000, time.UTC))),
+			option.Some(HostName("localhost")),
+			option.Some(AppName("/usr/local/bin/busybox")),
+			option.Some(ProcessID("1234")),
+			option.Some(MessageID("ID47")),
+		),
+		[]Metadata{
+			NewMetadata("exampleSDID@0"),
+			NewMetadata("exampleSDID@1", NewMetadataParam("eventID", "1011"), NewMetadataParam("eventSource", "Application Log")),
+		},
+		"hello, syslog!",
+	)
+
+	tests := []*test{
+		{
+			name:    "minimum",
+			message: newTestMessage("hello, syslog!"),
+			want:    "NOTE  hello, syslog!",
+		},
+		{
+			name:    "full",
+			message: message,
+			want:    `12:34:56.789 ERROR busybox[1234] ID47: hello, syslog! [exampleSDID@0] exampleSDID@1.eventID=1011 exampleSDID@1.eventSource="Application Log"`,
+		},
+		{
+			name:      "time format",
+			formatter: ConsoleFormatter{TimeFormat: time.Kitchen},
+			message:   message,
+			want:      `12:34PM ERROR busybox[1234] ID47: hello, syslog! [exampleSDID@0] exampleSDID@1.eventID=1011 exampleSDID@1.eventSource="Application Log"`,
+		},
+		{
+			name:      "color",
+			formatter: ConsoleFormatter{Color: true},
+			message:   newTestMessage("hello, syslog!"),
+			want:      "\x1b[36mNOTE \x1b[0m hello, syslog!",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if isTerminal(f) {
+		t.Fatalf("want=%v, got=%v.", false, true)
+	}
+}
+
+func TestDefaultFormatter(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	if _, ok := defaultFormatter().(RFC5424Formatter); !ok {
+		t.Fatalf("want=%T, got=%T.", RFC5424Formatter{}, defaultFormatter())
+	}
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"context"
+)
+
+type loggerKey struct{}
+
+type metadataKey struct{}
+
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the Logger stored by NewContext, or the package-level logger.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return logger
+	}
+	return std
+}
+
+// ContextWithMetadata merges meta into the Metadata already stored in ctx.
+func ContextWithMetadata(ctx context.Context, meta ...Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, mergeMetadata(MetadataFromContext(ctx), meta))
+}
+
+func MetadataFromContext(ctx context.Context) []Metadata {
+	meta, _ := ctx.Value(metadataKey{}).([]Metadata)
+	return meta
+}
+
+func logAt(logger Logger, severity Severity, msg ...any) error {
+	switch severity {
+	case SeverityEmergency:
+		return logger.Emergency(msg...)
+	case SeverityAlert:
+		return logger.Alert(msg...)
+	case SeverityCritical:
+		return logger.Critical(msg...)
+	case SeverityError:
+		return logger.Error(msg...)
+	case SeverityWarning:
+		return logger.Warning(msg...)
+	case SeverityNotice:
+		return logger.Notice(msg...)
+	case SeverityInformational:
+		return logger.Info(msg...)
+	default:
+		return logger.Debug(msg...)
+	}
+}
+
+func writeContext(ctx context.Context, severity Severity, msg []any) error {
+	logger := FromContext(ctx)
+	if !logger.Enabled(severity) {
+		return nil
+	}
+	if meta := MetadataFromContext(ctx); len(meta) > 0 {
+		logger = logger.With(meta...)
+	}
+	return logAt(logger, severity, msg...)
+}
+
+func EmergencyContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityEmergency, msg)
+}
+
+func AlertContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityAlert, msg)
+}
+
+func CriticalContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityCritical, msg)
+}
+
+func ErrorContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityError, msg)
+}
+
+func WarningContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityWarning, msg)
+}
+
+func NoticeContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityNotice, msg)
+}
+
+func InfoContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityInformational, msg)
+}
+
+func DebugContext(ctx context.Context, msg ...any) error {
+	return writeContext(ctx, SeverityDebug, msg)
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"context"
+	"github.com/a-skua/busybox-go/option"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if want, got := Logger(std), FromContext(context.Background()); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+
+	log := &Log{Writer: &messageWriter{}}
+	if want, got := Logger(log), FromContext(NewContext(context.Background(), log)); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func TestContextWithMetadata(t *testing.T) {
+	ctx := ContextWithMetadata(context.Background(), NewMetadata("req@1", NewMetadataParam("id", "abc")))
+	child := ContextWithMetadata(ctx, NewMetadata("req@1", NewMetadataParam("user", "alice")), NewMetadata("tenant@1", NewMetadataParam("id", "t1")))
+
+	if want, got := `[[req@1 id="abc"]]`, metadataString(MetadataFromContext(ctx)); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+	if want, got := `[[req@1 id="abc" user="alice"] [tenant@1 id="t1"]]`, metadataString(MetadataFromContext(child)); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func metadataString(meta []Metadata) string {
+	s := "["
+	for i, m := range meta {
+		if i > 0 {
+			s += " "
+		}
+		s += m.String()
+	}
+	return s + "]"
+}
+
+func TestInfoContext(t *testing.T) {
+	w := &messageWriter{}
+	log := &Log{
+		Facility: FacilityUserLevelMessages,
+		Version:  1,
+		Metadata: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+		Writer:   w,
+		Level:    SeverityInformational,
+	}
+
+	ctx := NewContext(context.Background(), log)
+	ctx = ContextWithMetadata(ctx, NewMetadata("req@1", NewMetadataParam("id", "abc")))
+
+	InfoContext(ctx, "handled")
+	DebugContext(ctx, "filtered")
+	ErrorContext(ctx, NewMetadata("req@1", NewMetadataParam("status", "500")), "failed")
+
+	want := []string{
+		`<14>1 - - - - - [app@1 version="1.0"][req@1 id="abc"] handled`,
+		`<11>1 - - - - - [app@1 version="1.0"][req@1 id="abc" status="500"] failed`,
+	}
+	if len(want) != len(w.messages) {
+		t.Fatalf("want=%v, got=%v.", len(want), len(w.messages))
+	}
+	for i, msg := range w.messages {
+		msg.Header.Timestamp = option.None[Timestamp]()
+		if want[i] != msg.String() {
+			t.Fatalf("want=%v, got=%v.", want[i], msg.String())
+		}
+	}
+}
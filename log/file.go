@@ -0,0 +1,144 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileCheckInterval limits how often Write checks whether the file was moved.
+const fileCheckInterval = time.Second
+
+// FileWriter appends Messages to a file whose rotation is left to an external
+// tool such as logrotate. The file is reopened by Reopen, and by Write once its
+// path no longer refers to the open file because it was moved or removed. Write
+// checks the path at most once per second, so Messages written in the meantime
+// still go to the moved file.
+// A file truncated in place (copytruncate) keeps being written from its new end
+// since it is opened in append mode.
+type FileWriter struct {
+	Formatter Formatter
+	// ErrorHandler, if set, is called when the file cannot be reopened automatically.
+	// Writes then continue to the old file.
+	ErrorHandler func(error)
+
+	path string
+	now  func() time.Time
+
+	mu      sync.Mutex
+	file    *os.File
+	info    os.FileInfo
+	checked time.Time
+	closed  bool
+}
+
+func NewFileWriter(path string) (*FileWriter, error) {
+	w := &FileWriter{
+		path: path,
+		now:  time.Now,
+	}
+	if err := w.reopen(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// reopen opens the path before closing the old file so that a failure leaves w writable.
+func (w *FileWriter) reopen() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	old := w.file
+	w.file = file
+	w.info = info
+	w.checked = w.now()
+	if old == nil {
+		return nil
+	}
+	return old.Close()
+}
+
+// moved reports whether the path refers to another file than the open one.
+// It reports false without checking within fileCheckInterval of the last check.
+func (w *FileWriter) moved() bool {
+	now := w.now()
+	if now.Sub(w.checked) < fileCheckInterval {
+		return false
+	}
+	w.checked = now
+
+	info, err := os.Stat(w.path)
+	return err != nil || !os.SameFile(info, w.info)
+}
+
+// Reopen closes the file and opens the path again.
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.reopen()
+}
+
+func (w *FileWriter) Write(msg *Message) error {
+	data, err := format(w.Formatter, msg)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if w.moved() {
+		if err := w.reopen(); err != nil && w.ErrorHandler != nil {
+			w.ErrorHandler(err)
+		}
+	}
+
+	_, err = w.file.Write(append(data, '\n'))
+	return err
+}
+
+// Flush commits the file to stable storage.
+func (w *FileWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	return w.file.Close()
+}
+
+// reopenOnSignal is called for each SIGHUP by NotifyReopenSignal.
+func (w *FileWriter) reopenOnSignal() {
+	if err := w.Reopen(); err != nil && !errors.Is(err, ErrWriterClosed) && w.ErrorHandler != nil {
+		w.ErrorHandler(err)
+	}
+}
@@ -0,0 +1,161 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileWriter_Write(t *testing.T) {
+	type test struct {
+		name    string
+		rotate  func(t *testing.T, path string)
+		want    string
+		wantOld string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			w, err := NewFileWriter(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			clock := &fakeClock{t: w.checked}
+			w.now = clock.now
+
+			if err := w.Write(newTestMessage("foo")); err != nil {
+				t.Fatal(err)
+			}
+			tt.rotate(t, path)
+			clock.advance(fileCheckInterval)
+			if err := w.Write(newTestMessage("bar")); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := readFile(t, path); tt.want != got {
+				t.Fatalf("want=%q, got=%q.", tt.want, got)
+			}
+			if tt.wantOld == "" {
+				return
+			}
+			if got := readFile(t, path+".1"); tt.wantOld != got {
+				t.Fatalf("want=%q, got=%q.", tt.wantOld, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:   "not rotated",
+			rotate: func(t *testing.T, path string) {},
+			want:   "<165>1 - - - - - - foo\n<165>1 - - - - - - bar\n",
+		},
+		{
+			name: "moved",
+			rotate: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:    "<165>1 - - - - - - bar\n",
+			wantOld: "<165>1 - - - - - - foo\n",
+		},
+		{
+			name: "moved and recreated",
+			rotate: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:    "<165>1 - - - - - - bar\n",
+			wantOld: "<165>1 - - - - - - foo\n",
+		},
+		{
+			name: "removed",
+			rotate: func(t *testing.T, path string) {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "<165>1 - - - - - - bar\n",
+		},
+		{
+			name: "truncated",
+			rotate: func(t *testing.T, path string) {
+				if err := os.Truncate(path, 0); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: "<165>1 - - - - - - bar\n",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestFileWriter_Write_checkInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	clock := &fakeClock{t: w.checked}
+	w.now = clock.now
+
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	// the move is not noticed until fileCheckInterval has passed.
+	clock.advance(fileCheckInterval - time.Millisecond)
+	if err := w.Write(newTestMessage("foo")); err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Millisecond)
+	if err := w.Write(newTestMessage("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := "<165>1 - - - - - - bar\n", readFile(t, path); want != got {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+	if want, got := "<165>1 - - - - - - foo\n", readFile(t, path+".1"); want != got {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+}
+
+func TestFileWriter_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Write(newTestMessage("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(newTestMessage("bar")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Reopen(); err != ErrWriterClosed {
+		t.Fatalf("want-error=%v, error=%v.", ErrWriterClosed, err)
+	}
+
+	if want, got := "<165>1 - - - - - - foo\n<165>1 - - - - - - bar\n", readFile(t, path); want != got {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+}
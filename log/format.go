@@ -0,0 +1,116 @@
+package log
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type Formatter interface {
+	Format(*Message) ([]byte, error)
+}
+
+type TimePrecision uint8
+
+const (
+	// TimePrecisionDefault writes up to 6 fractional digits without trailing zeros.
+	TimePrecisionDefault TimePrecision = iota
+	TimePrecisionSeconds
+	TimePrecisionMillis
+	TimePrecisionMicros
+)
+
+var timePrecisionLayouts = [...]string{
+	TimePrecisionDefault: TimestampLayout,
+	TimePrecisionSeconds: "2006-01-02T15:04:05Z07:00",
+	TimePrecisionMillis:  "2006-01-02T15:04:05.000Z07:00",
+	TimePrecisionMicros:  "2006-01-02T15:04:05.000000Z07:00",
+}
+
+type TimeZone uint8
+
+const (
+	// TimeZoneOriginal keeps the offset of the Timestamp.
+	TimeZoneOriginal TimeZone = iota
+	// TimeZoneUTC writes the Timestamp in UTC with the "Z" offset.
+	TimeZoneUTC
+	TimeZoneLocal
+)
+
+type RFC5424Formatter struct {
+	Precision TimePrecision
+	Zone      TimeZone
+}
+
+func (f RFC5424Formatter) Format(msg *Message) ([]byte, error) {
+	timestamp := "-"
+	if msg.Header.Timestamp.Valid {
+		timestamp = f.timestamp(time.Time(msg.Header.Timestamp.Value))
+	}
+	return []byte(msg.string(msg.Header.string(timestamp))), nil
+}
+
+func (f RFC5424Formatter) timestamp(t time.Time) string {
+	switch f.Zone {
+	case TimeZoneUTC:
+		t = t.UTC()
+	case TimeZoneLocal:
+		t = t.Local()
+	}
+
+	layout := TimestampLayout
+	if int(f.Precision) < len(timePrecisionLayouts) {
+		layout = timePrecisionLayouts[f.Precision]
+	}
+	return t.Format(layout)
+}
+
+// format falls back to RFC 5424 when no Formatter is configured.
+func format(f Formatter, msg *Message) ([]byte, error) {
+	if f == nil {
+		f = RFC5424Formatter{}
+	}
+	return f.Format(msg)
+}
+
+type ioWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	formatter Formatter
+}
+
+// NewWriter writes each Message as one line to w.
+func NewWriter(w io.Writer, f Formatter) Writer {
+	return &ioWriter{
+		w:         w,
+		formatter: f,
+	}
+}
+
+// Flush flushes w if it has a Flush method such as bufio.Writer. The io.Writer
+// is not closed by Close since it is owned by the caller.
+func (w *ioWriter) Flush(ctx context.Context) error {
+	f, ok := w.w.(interface{ Flush() error })
+	if !ok {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return f.Flush()
+}
+
+func (w *ioWriter) Write(msg *Message) error {
+	data, err := format(w.formatter, msg)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+	"net"
+	"testing"
+	"time"
+)
+
+type errFormatter struct{}
+
+var errFormat = errors.New("format error")
+
+func (f errFormatter) Format(*Message) ([]byte, error) {
+	return nil, errFormat
+}
+
+func TestRFC5424Formatter_Format(t *testing.T) {
+	msg := newTestMessage("foo", "bar")
+	got, err := RFC5424Formatter{}.Format(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := msg.String(); want != string(got) {
+		t.Fatalf("want=%v, got=%s.", want, got)
+	}
+}
+
+func TestRFC5424Formatter_timestamp(t *testing.T) {
+	type test struct {
+		name      string
+		formatter RFC5424Formatter
+		timestamp time.Time
+		want      string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newTestMessage("foo")
+			msg.Header.Timestamp = option.Some(Timestamp(tt.timestamp))
+			got, err := tt.formatter.Format(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "<165>1 " + tt.want + " - - - - - foo"; want != string(got) {
+				t.Fatalf("want=%v, got=%s.", want, got)
+			}
+		})
+	}
+
+	jst := time.FixedZone("JST", 9*60*60)
+	timestamp := time.Date(2023, 02, 16, 21, 34, 56, 120456789, jst)
+
+	tests := []*test{
+		{
+			name:      "default",
+			timestamp: timestamp,
+			want:      "2023-02-16T21:34:56.120456+09:00",
+		},
+		{
+			name:      "default trims trailing zeros",
+			timestamp: time.Date(2023, 02, 16, 21, 34, 56, 120000000, jst),
+			want:      "2023-02-16T21:34:56.12+09:00",
+		},
+		{
+			name:      "seconds",
+			formatter: RFC5424Formatter{Precision: TimePrecisionSeconds},
+			timestamp: timestamp,
+			want:      "2023-02-16T21:34:56+09:00",
+		},
+		{
+			name:      "millis",
+			formatter: RFC5424Formatter{Precision: TimePrecisionMillis},
+			timestamp: timestamp,
+			want:      "2023-02-16T21:34:56.120+09:00",
+		},
+		{
+			name:      "micros",
+			formatter: RFC5424Formatter{Precision: TimePrecisionMicros},
+			timestamp: time.Date(2023, 02, 16, 21, 34, 56, 0, jst),
+			want:      "2023-02-16T21:34:56.000000+09:00",
+		},
+		{
+			name:      "utc",
+			formatter: RFC5424Formatter{Precision: TimePrecisionMillis, Zone: TimeZoneUTC},
+			timestamp: timestamp,
+			want:      "2023-02-16T12:34:56.120Z",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	type test struct {
+		name      string
+		formatter Formatter
+		message   *Message
+		want      string
+		wantErr   bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := NewWriter(buf, tt.formatter).Write(tt.message)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if got := buf.String(); tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	message := NewMessage(
+		NewHeader(
+			NewPriority(FacilityLocalUse4, SeverityNotice),
+			1,
+			option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC))),
+			option.Some(HostName("localhost")),
+			option.Some(AppName("busybox")),
+			option.None[ProcessID](),
+			option.None[MessageID](),
+		),
+		[]Metadata{},
+		"hello, syslog!",
+	)
+
+	tests := []*test{
+		{
+			name:    "default RFC 5424",
+			message: message,
+			want:    "<165>1 2023-02-16T12:34:56Z localhost busybox - - - hello, syslog!\n",
+		},
+		{
+			name:      "RFC 3164",
+			formatter: RFC3164Formatter{Location: time.UTC},
+			message:   message,
+			want:      "<165>Feb 16 12:34:56 localhost busybox: hello, syslog!\n",
+		},
+		{
+			name:      "format error",
+			formatter: errFormatter{},
+			message:   message,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestUDPWriter_Formatter(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	w, err := NewUDPWriter(server.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Formatter = RFC3164Formatter{Location: time.UTC}
+
+	msg := newTestMessage("hello, syslog!")
+	msg.Header.Timestamp = option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC)))
+	msg.Header.Host = option.Some(HostName("localhost"))
+	if err := w.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := server.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "<165>Feb 16 12:34:56 localhost hello, syslog!", string(buf[:n]); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/a-skua/busybox-go/log
 
-go 1.20
+go 1.21
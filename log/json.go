@@ -0,0 +1,177 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+)
+
+type messageJSON struct {
+	Facility       string                   `json:"facility"`
+	FacilityCode   Facility                 `json:"facility_code"`
+	Severity       string                   `json:"severity"`
+	SeverityCode   Severity                 `json:"severity_code"`
+	Version        Version                  `json:"version"`
+	Timestamp      option.Option[Timestamp] `json:"timestamp"`
+	Host           option.Option[HostName]  `json:"hostname"`
+	App            option.Option[AppName]   `json:"app_name"`
+	ProcessID      option.Option[ProcessID] `json:"procid"`
+	MessageID      option.Option[MessageID] `json:"msgid"`
+	StructuredData structuredDataJSON       `json:"structured_data"`
+	Message        string                   `json:"msg"`
+}
+
+// marshalJSON is json.Marshal without HTML escaping; json.Marshal still
+// escapes the result when it is called on a Message.
+func marshalJSON(v any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// structuredDataJSON keeps the order of SD-ELEMENTs and SD-PARAMs,
+// which a map based encoding would lose.
+type structuredDataJSON []Metadata
+
+func (sd structuredDataJSON) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, meta := range sd {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		id, err := marshalJSON(meta.ID)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(id)
+		buf.WriteString(":{")
+		for j, param := range meta.Params {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			name, err := marshalJSON(param.Name)
+			if err != nil {
+				return nil, err
+			}
+			value, err := marshalJSON(param.Value)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var errStructuredDataJSON = errors.New("log: structured_data must be an object of objects")
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return errStructuredDataJSON
+	}
+	return nil
+}
+
+func (sd *structuredDataJSON) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*sd = nil
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var metadata []Metadata
+	for dec.More() {
+		var id MetadataID
+		if err := dec.Decode(&id); err != nil {
+			return err
+		}
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		meta := NewMetadata(id)
+		for dec.More() {
+			var param MetadataParam
+			if err := dec.Decode(&param.Name); err != nil {
+				return err
+			}
+			if err := dec.Decode(&param.Value); err != nil {
+				return err
+			}
+			meta.Params = append(meta.Params, param)
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+		metadata = append(metadata, meta)
+	}
+
+	*sd = metadata
+	return expectDelim(dec, '}')
+}
+
+func (msg Message) MarshalJSON() ([]byte, error) {
+	return marshalJSON(messageJSON{
+		Facility:       msg.Header.Priority.Facility.String(),
+		FacilityCode:   msg.Header.Priority.Facility,
+		Severity:       msg.Header.Priority.Severity.String(),
+		SeverityCode:   msg.Header.Priority.Severity,
+		Version:        msg.Header.Version,
+		Timestamp:      msg.Header.Timestamp,
+		Host:           msg.Header.Host,
+		App:            msg.Header.App,
+		ProcessID:      msg.Header.ProcessID,
+		MessageID:      msg.Header.MessageID,
+		StructuredData: msg.Metadata,
+		Message:        msg.text(),
+	})
+}
+
+// UnmarshalJSON decodes the priority from facility_code and severity_code;
+// the names are informational only.
+func (msg *Message) UnmarshalJSON(data []byte) error {
+	var v messageJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*msg = Message{
+		Header: NewHeader(
+			NewPriority(v.FacilityCode, v.SeverityCode),
+			v.Version,
+			v.Timestamp,
+			v.Host,
+			v.App,
+			v.ProcessID,
+			v.MessageID,
+		),
+		Metadata: v.StructuredData,
+	}
+	if v.Message != "" {
+		msg.Message = []any{v.Message}
+	}
+	return nil
+}
+
+type JSONFormatter struct{}
+
+func (f JSONFormatter) Format(msg *Message) ([]byte, error) {
+	return marshalJSON(msg)
+}
@@ -0,0 +1,135 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/a-skua/busybox-go/option"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatter_Format(t *testing.T) {
+	type test struct {
+		name    string
+		message *Message
+		want    string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONFormatter{}.Format(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != string(got) {
+				t.Fatalf("want=%v, got=%s.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:    "minimum",
+			message: newTestMessage(),
+			want:    `{"facility":"local4","facility_code":20,"severity":"notice","severity_code":5,"version":1,"timestamp":null,"hostname":null,"app_name":null,"procid":null,"msgid":null,"structured_data":{},"msg":""}`,
+		},
+		{
+			name: "full",
+			message: NewMessage(
+				NewHeader(
+					NewPriority(FacilityUserLevelMessages, SeverityError),
+					1,
+					option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC))),
+					option.Some(HostName("localhost")),
+					option.Some(AppName("busybox")),
+					option.Some(ProcessID("1234")),
+					option.Some(MessageID("ID47")),
+				),
+				[]Metadata{
+					NewMetadata("exampleSDID@1", NewMetadataParam("z", "<1>"), NewMetadataParam("a", "\"2\"")),
+					NewMetadata("exampleSDID@0"),
+				},
+				"hello,",
+				"syslog!",
+			),
+			want: `{"facility":"user","facility_code":1,"severity":"error","severity_code":3,"version":1,"timestamp":"2023-02-16T12:34:56Z","hostname":"localhost","app_name":"busybox","procid":"1234","msgid":"ID47","structured_data":{"exampleSDID@1":{"z":"<1>","a":"\"2\""},"exampleSDID@0":{}},"msg":"hello, syslog!"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestMessage_UnmarshalJSON(t *testing.T) {
+	type test struct {
+		name    string
+		data    string
+		want    *Message
+		wantErr bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &Message{}
+			err := json.Unmarshal([]byte(tt.data), got)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "full",
+			data: `{"facility":"user","facility_code":1,"severity":"error","severity_code":3,"version":1,"timestamp":"2023-02-16T12:34:56Z","hostname":"localhost","app_name":"busybox","procid":"1234","msgid":"ID47","structured_data":{"exampleSDID@1":{"z":"<1>","a":"\"2\""},"exampleSDID@0":{}},"msg":"hello, syslog!"}`,
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityUserLevelMessages, SeverityError),
+					1,
+					option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC))),
+					option.Some(HostName("localhost")),
+					option.Some(AppName("busybox")),
+					option.Some(ProcessID("1234")),
+					option.Some(MessageID("ID47")),
+				),
+				[]Metadata{
+					NewMetadata("exampleSDID@1", NewMetadataParam("z", "<1>"), NewMetadataParam("a", "\"2\"")),
+					NewMetadata("exampleSDID@0"),
+				},
+				"hello, syslog!",
+			),
+		},
+		{
+			name: "null values",
+			data: `{"facility_code":20,"severity_code":5,"version":1,"timestamp":null,"hostname":null,"app_name":null,"procid":null,"msgid":null,"structured_data":null,"msg":""}`,
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityLocalUse4, SeverityNotice),
+					1,
+					option.None[Timestamp](),
+					option.None[HostName](),
+					option.None[AppName](),
+					option.None[ProcessID](),
+					option.None[MessageID](),
+				),
+				nil,
+			),
+		},
+		{
+			name:    "invalid structured data",
+			data:    `{"structured_data":{"id":["a"]}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
@@ -0,0 +1,205 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var severityAliases = map[string]Severity{
+	"emerg":         SeverityEmergency,
+	"panic":         SeverityEmergency,
+	"crit":          SeverityCritical,
+	"err":           SeverityError,
+	"warn":          SeverityWarning,
+	"informational": SeverityInformational,
+}
+
+// ParseSeverity accepts the names of Severity.String, common syslog aliases and numeric values.
+func ParseSeverity(s string) (Severity, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for severity, n := range severityNames {
+		if n == name {
+			return Severity(severity), nil
+		}
+	}
+	if severity, ok := severityAliases[name]; ok {
+		return severity, nil
+	}
+	if n, err := strconv.Atoi(name); err == nil && 0 <= n && n < len(severityNames) {
+		return Severity(n), nil
+	}
+	return 0, errors.New("log: unknown severity: " + s)
+}
+
+// LevelVar is a Leveler that can be changed while it is in use.
+// The zero value is SeverityDebug.
+type LevelVar struct {
+	v atomic.Uint32
+}
+
+func NewLevelVar(severity Severity) *LevelVar {
+	level := &LevelVar{}
+	level.Set(severity)
+	return level
+}
+
+func (level *LevelVar) Level() Severity {
+	v := level.v.Load()
+	if v == 0 {
+		return SeverityDebug
+	}
+	return Severity(v - 1)
+}
+
+func (level *LevelVar) Set(severity Severity) {
+	level.v.Store(uint32(severity) + 1)
+}
+
+// step moves the level by delta, clamped between SeverityEmergency and SeverityDebug.
+func (level *LevelVar) step(delta int) Severity {
+	for {
+		old := level.v.Load()
+		current := int(level.Level())
+		next := current + delta
+		if next < int(SeverityEmergency) {
+			next = int(SeverityEmergency)
+		}
+		if next > int(SeverityDebug) {
+			next = int(SeverityDebug)
+		}
+		if level.v.CompareAndSwap(old, uint32(next)+1) {
+			return Severity(next)
+		}
+	}
+}
+
+func (level *LevelVar) String() string {
+	return level.Level().String()
+}
+
+func (level *LevelVar) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
+}
+
+func (level *LevelVar) UnmarshalText(data []byte) error {
+	severity, err := ParseSeverity(string(data))
+	if err != nil {
+		return err
+	}
+	level.Set(severity)
+	return nil
+}
+
+// levelFromBody accepts a bare level, a JSON string or the object returned by GET,
+// so that a client can send back what it received.
+func levelFromBody(name string, body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+	switch {
+	case bytes.HasPrefix(body, []byte(`"`)):
+		var level string
+		if err := json.Unmarshal(body, &level); err != nil {
+			return nil, err
+		}
+		return []byte(level), nil
+	case bytes.HasPrefix(body, []byte("{")):
+		var levels map[string]string
+		if err := json.Unmarshal(body, &levels); err != nil {
+			return nil, err
+		}
+		level, ok := levels[name]
+		if !ok || len(levels) != 1 {
+			return nil, errors.New("log: body must hold only the level of " + strconv.Quote(name))
+		}
+		return []byte(level), nil
+	default:
+		return body, nil
+	}
+}
+
+// LevelHandler serves the levels of registered loggers.
+//
+//	GET /?logger=name  returns {"name":"info"}, or every logger without the query.
+//	PUT /?logger=name  sets the level from the request body: debug, "debug" or {"name":"debug"}.
+type LevelHandler struct {
+	mu     sync.RWMutex
+	levels map[string]*LevelVar
+}
+
+func NewLevelHandler() *LevelHandler {
+	return &LevelHandler{
+		levels: map[string]*LevelVar{},
+	}
+}
+
+func (h *LevelHandler) Register(name string, level *LevelVar) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.levels[name] = level
+}
+
+func (h *LevelHandler) lookup(name string) (*LevelVar, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	level, ok := h.levels[name]
+	return level, ok
+}
+
+func (h *LevelHandler) writeLevels(w http.ResponseWriter, levels map[string]*LevelVar) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(levels)
+}
+
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("logger")
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if name == "" {
+			h.mu.RLock()
+			defer h.mu.RUnlock()
+			h.writeLevels(w, h.levels)
+			return
+		}
+		level, ok := h.lookup(name)
+		if !ok {
+			http.Error(w, "unknown logger: "+name, http.StatusNotFound)
+			return
+		}
+		h.writeLevels(w, map[string]*LevelVar{name: level})
+
+	case http.MethodPut:
+		level, ok := h.lookup(name)
+		if !ok {
+			http.Error(w, "unknown logger: "+name, http.StatusNotFound)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1024))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		text, err := levelFromBody(name, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := level.UnmarshalText(text); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeLevels(w, map[string]*LevelVar{name: level})
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
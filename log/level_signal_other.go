@@ -0,0 +1,8 @@
+//go:build !unix
+
+package log
+
+// NotifyLevelSignals is a no-op on platforms without SIGUSR1 and SIGUSR2.
+func NotifyLevelSignals(level *LevelVar) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// NotifyLevelSignals makes SIGUSR1 raise and SIGUSR2 lower the verbosity of level
+// until stop is called. stop may be called more than once.
+func NotifyLevelSignals(level *LevelVar) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGUSR1 {
+					level.step(1)
+				} else {
+					level.step(-1)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
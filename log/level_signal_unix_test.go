@@ -0,0 +1,37 @@
+//go:build unix
+
+package log
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyLevelSignals(t *testing.T) {
+	level := NewLevelVar(SeverityInformational)
+	stop := NotifyLevelSignals(level)
+	defer stop()
+
+	wait := func(want Severity) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for level.Level() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("want=%v, got=%v.", want, level.Level())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	wait(SeverityDebug)
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	wait(SeverityInformational)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	wait(SeverityNotice)
+
+	// stop is deferred as well.
+	stop()
+}
@@ -0,0 +1,214 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	type test struct {
+		name    string
+		value   string
+		want    Severity
+		wantErr bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSeverity(tt.value)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:  "name",
+			value: "warning",
+			want:  SeverityWarning,
+		},
+		{
+			name:  "upper case",
+			value: "DEBUG",
+			want:  SeverityDebug,
+		},
+		{
+			name:  "alias",
+			value: "crit",
+			want:  SeverityCritical,
+		},
+		{
+			name:  "number",
+			value: "6",
+			want:  SeverityInformational,
+		},
+		{
+			name:    "out of range",
+			value:   "8",
+			wantErr: true,
+		},
+		{
+			name:    "unknown",
+			value:   "verbose",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestLevelVar(t *testing.T) {
+	level := &LevelVar{}
+	if want, got := SeverityDebug, level.Level(); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+
+	level.Set(SeverityEmergency)
+	if want, got := SeverityEmergency, level.Level(); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+	if want, got := SeverityEmergency, level.step(-1); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+
+	level.Set(SeverityInformational)
+	if want, got := SeverityDebug, level.step(1); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+	if want, got := SeverityDebug, level.step(1); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+
+	log := &Log{Writer: &messageWriter{}, Level: level}
+	level.Set(SeverityError)
+	if log.Enabled(SeverityWarning) {
+		t.Fatalf("want=%v, got=%v.", false, true)
+	}
+	level.Set(SeverityWarning)
+	if !log.Enabled(SeverityWarning) {
+		t.Fatalf("want=%v, got=%v.", true, false)
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	type test struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantLevel  Severity
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			level := NewLevelVar(SeverityInformational)
+			h := NewLevelHandler()
+			h.Register("app", level)
+			h.Register("db", NewLevelVar(SeverityError))
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if tt.wantStatus != rec.Code {
+				t.Fatalf("want=%v, got=%v.", tt.wantStatus, rec.Code)
+			}
+			if tt.wantBody != "" && tt.wantBody != rec.Body.String() {
+				t.Fatalf("want=%v, got=%v.", tt.wantBody, rec.Body.String())
+			}
+			if tt.wantLevel != level.Level() {
+				t.Fatalf("want=%v, got=%v.", tt.wantLevel, level.Level())
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:       "get all",
+			method:     http.MethodGet,
+			target:     "/",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"app\":\"info\",\"db\":\"error\"}\n",
+			wantLevel:  SeverityInformational,
+		},
+		{
+			name:       "get one",
+			method:     http.MethodGet,
+			target:     "/?logger=db",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"db\":\"error\"}\n",
+			wantLevel:  SeverityInformational,
+		},
+		{
+			name:       "put",
+			method:     http.MethodPut,
+			target:     "/?logger=app",
+			body:       "debug\n",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"app\":\"debug\"}\n",
+			wantLevel:  SeverityDebug,
+		},
+		{
+			name:       "put json string",
+			method:     http.MethodPut,
+			target:     "/?logger=app",
+			body:       `"debug"`,
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"app\":\"debug\"}\n",
+			wantLevel:  SeverityDebug,
+		},
+		{
+			name:       "put body of get",
+			method:     http.MethodPut,
+			target:     "/?logger=app",
+			body:       "{\"app\":\"warning\"}\n",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"app\":\"warning\"}\n",
+			wantLevel:  SeverityWarning,
+		},
+		{
+			name:       "put object of other logger",
+			method:     http.MethodPut,
+			target:     "/?logger=app",
+			body:       `{"cache":"debug"}`,
+			wantStatus: http.StatusBadRequest,
+			wantLevel:  SeverityInformational,
+		},
+		{
+			name:       "put invalid level",
+			method:     http.MethodPut,
+			target:     "/?logger=app",
+			body:       "verbose",
+			wantStatus: http.StatusBadRequest,
+			wantLevel:  SeverityInformational,
+		},
+		{
+			name:       "unknown logger",
+			method:     http.MethodPut,
+			target:     "/?logger=cache",
+			body:       "debug",
+			wantStatus: http.StatusNotFound,
+			wantLevel:  SeverityInformational,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/?logger=app",
+			body:       "debug",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantLevel:  SeverityInformational,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// Flusher is implemented by Writers that buffer Messages.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// ContextCloser is implemented by Writers whose closing may wait, bounded by ctx:
+// AsyncWriter, MultiWriter, RotatingFileWriter and ValidatingWriter. TCPWriter,
+// UDPWriter, UnixWriter and FileWriter implement io.Closer instead.
+// CloseWriter closes either kind.
+type ContextCloser interface {
+	Close(ctx context.Context) error
+}
+
+// FlushWriter flushes w if it is a Flusher.
+func FlushWriter(ctx context.Context, w Writer) error {
+	if f, ok := w.(Flusher); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// CloseWriter closes w if it is a ContextCloser or an io.Closer. The latter is used by
+// Writers that cannot bound closing by a deadline.
+func CloseWriter(ctx context.Context, w Writer) error {
+	switch c := w.(type) {
+	case ContextCloser:
+		return c.Close(ctx)
+	case io.Closer:
+		return c.Close()
+	}
+	return nil
+}
+
+// Flush flushes the Writer if it buffers Messages.
+func (log *Log) Flush(ctx context.Context) error {
+	return FlushWriter(ctx, log.Writer)
+}
+
+// Shutdown flushes and closes the Writer, which is shared with the loggers derived by With.
+func (log *Log) Shutdown(ctx context.Context) error {
+	return errors.Join(FlushWriter(ctx, log.Writer), CloseWriter(ctx, log.Writer))
+}
+
+// Default returns the package-level logger. Its fields may be changed before logging starts.
+func Default() *Log {
+	return std
+}
+
+// Shutdown flushes and closes the Writer of the package-level logger.
+func Shutdown(ctx context.Context) error {
+	return std.Shutdown(ctx)
+}
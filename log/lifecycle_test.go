@@ -0,0 +1,134 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+// lifecycleWriter records Flush and Close calls.
+type lifecycleWriter struct {
+	messageWriter
+	flushed  int
+	closed   int
+	flushErr error
+	closeErr error
+}
+
+func (w *lifecycleWriter) Flush(ctx context.Context) error {
+	w.flushed++
+	return w.flushErr
+}
+
+func (w *lifecycleWriter) Close(ctx context.Context) error {
+	w.closed++
+	return w.closeErr
+}
+
+func TestLog_Shutdown(t *testing.T) {
+	type test struct {
+		name    string
+		writer  Writer
+		wantErr []error
+	}
+
+	errFlush := errors.New("flush error")
+	errClose := errors.New("close error")
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &Log{Writer: tt.writer}
+			err := log.Shutdown(context.Background())
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Fatalf("want-error=%v, error=%v.", want, err)
+				}
+			}
+			if len(tt.wantErr) == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if w, ok := tt.writer.(*lifecycleWriter); ok && (w.flushed != 1 || w.closed != 1) {
+				t.Fatalf("want=%v, got=%v.", [2]int{1, 1}, [2]int{w.flushed, w.closed})
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:   "flush and close",
+			writer: &lifecycleWriter{},
+		},
+		{
+			name:   "no lifecycle",
+			writer: &messageWriter{},
+		},
+		{
+			name:    "join errors",
+			writer:  &lifecycleWriter{flushErr: errFlush, closeErr: errClose},
+			wantErr: []error{errFlush, errClose},
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+// ioCloserWriter implements io.Closer instead of ContextCloser.
+type ioCloserWriter struct {
+	messageWriter
+	closed int
+}
+
+func (w *ioCloserWriter) Close() error {
+	w.closed++
+	return nil
+}
+
+func TestCloseWriter(t *testing.T) {
+	ctxCloser := &lifecycleWriter{}
+	ioCloser := &ioCloserWriter{}
+	for _, w := range []Writer{ctxCloser, ioCloser, &messageWriter{}} {
+		if err := CloseWriter(context.Background(), w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ctxCloser.closed != 1 || ioCloser.closed != 1 {
+		t.Fatalf("want=%v, got=%v.", [2]int{1, 1}, [2]int{ctxCloser.closed, ioCloser.closed})
+	}
+}
+
+func TestNewWriter_Flush(t *testing.T) {
+	buf := &bytes.Buffer{}
+	b := bufio.NewWriter(buf)
+	w := NewWriter(b, nil)
+	if err := w.Write(newTestMessage("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("want=%v, got=%v.", 0, buf.Len())
+	}
+	if err := FlushWriter(context.Background(), w); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "<165>1 - - - - - - foo\n", buf.String(); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func TestAsyncWriter_Close_propagate(t *testing.T) {
+	w := &lifecycleWriter{}
+	a := NewAsyncWriter(w, 4, DropPolicyBlock)
+	a.Write(newTestMessage("foo"))
+	if err := a.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.messages) != 1 || w.flushed != 1 || w.closed != 1 {
+		t.Fatalf("want=%v, got=%v.", [3]int{1, 1, 1}, [3]int{len(w.messages), w.flushed, w.closed})
+	}
+}
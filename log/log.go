@@ -10,6 +10,12 @@ import (
 	"time"
 )
 
+// Logger writes messages at each Severity. Leading MessageID, Metadata or []Metadata
+// arguments of the non-printf methods set the MSGID and add STRUCTURED-DATA to that message.
+//
+// A call below the threshold returns without allocating, but arguments passed as any are
+// kept by the Writer when enabled, so the caller boxes non-constant values on the heap
+// before the call. Guard hot paths with Enabled to avoid that cost.
 type Logger interface {
 	Emergency(msg ...any) error
 	Alert(msg ...any) error
@@ -19,6 +25,18 @@ type Logger interface {
 	Notice(msg ...any) error
 	Info(msg ...any) error
 	Debug(msg ...any) error
+	Emergencyf(format string, args ...any) error
+	Alertf(format string, args ...any) error
+	Criticalf(format string, args ...any) error
+	Errorf(format string, args ...any) error
+	Warningf(format string, args ...any) error
+	Noticef(format string, args ...any) error
+	Infof(format string, args ...any) error
+	Debugf(format string, args ...any) error
+	Enabled(severity Severity) bool
+	With(meta ...Metadata) Logger
+	WithMessageID(id MessageID) Logger
+	WithApp(app AppName) Logger
 }
 
 type Facility uint8
@@ -50,6 +68,40 @@ const (
 	FacilityLocalUse7
 )
 
+var facilityNames = [...]string{
+	FacilityKernelMessages:                       "kern",
+	FacilityUserLevelMessages:                    "user",
+	FacilityMailSystem:                           "mail",
+	FacilitySystemDaemons:                        "daemon",
+	FacilitySecurityOrAuthorizationMessages0:     "auth",
+	FacilityMessagesGeneratedInternallyBySyslogd: "syslog",
+	FacilityLinePrinterSubsystem:                 "lpr",
+	FacilityNetworkNewsSubsystem:                 "news",
+	FacilityUUCPSubsystem:                        "uucp",
+	FacilityClockDaemon0:                         "cron",
+	FacilitySecurityOrAuthorizationMessages1:     "authpriv",
+	FacilityFTPDaemon:                            "ftp",
+	FacilityNTPSubsystem:                         "ntp",
+	FacilityLogAudit:                             "audit",
+	FacilityLogAlert:                             "alert",
+	FacilityClockDaemon1:                         "clock",
+	FacilityLocalUse0:                            "local0",
+	FacilityLocalUse1:                            "local1",
+	FacilityLocalUse2:                            "local2",
+	FacilityLocalUse3:                            "local3",
+	FacilityLocalUse4:                            "local4",
+	FacilityLocalUse5:                            "local5",
+	FacilityLocalUse6:                            "local6",
+	FacilityLocalUse7:                            "local7",
+}
+
+func (f Facility) String() string {
+	if int(f) < len(facilityNames) {
+		return facilityNames[f]
+	}
+	return strconv.Itoa(int(f))
+}
+
 type Severity uint8
 
 const (
@@ -63,6 +115,32 @@ const (
 	SeverityDebug
 )
 
+var severityNames = [...]string{
+	SeverityEmergency:     "emergency",
+	SeverityAlert:         "alert",
+	SeverityCritical:      "critical",
+	SeverityError:         "error",
+	SeverityWarning:       "warning",
+	SeverityNotice:        "notice",
+	SeverityInformational: "info",
+	SeverityDebug:         "debug",
+}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return strconv.Itoa(int(s))
+}
+
+type Leveler interface {
+	Level() Severity
+}
+
+func (s Severity) Level() Severity {
+	return s
+}
+
 type Priority struct {
 	Facility Facility
 	Severity Severity
@@ -91,14 +169,21 @@ func TimestampNow() Timestamp {
 	return Timestamp(time.Now())
 }
 
+// TimestampLayout keeps TIME-SECFRAC within the 6 digits allowed by RFC 5424.
+const TimestampLayout = "2006-01-02T15:04:05.999999Z07:00"
+
 func (t Timestamp) String() string {
-	return time.Time(t).Format(time.RFC3339Nano)
+	return time.Time(t).Format(TimestampLayout)
 }
 
 func (t Timestamp) MarshalJSON() ([]byte, error) {
 	return time.Time(t).MarshalJSON()
 }
 
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	return (*time.Time)(t).UnmarshalJSON(data)
+}
+
 type HostName string
 
 func (host HostName) String() string {
@@ -153,10 +238,15 @@ func NewHeader(pri Priority, ver Version, time option.Option[Timestamp], host op
 }
 
 func (h Header) String() string {
+	return h.string(optionToString(h.Timestamp))
+}
+
+// string renders h with the TIMESTAMP already formatted.
+func (h Header) string(timestamp string) string {
 	return h.Priority.String() +
 		strconv.Itoa(int(h.Version)) +
 		" " +
-		optionToString(h.Timestamp) +
+		timestamp +
 		" " +
 		optionToString(h.Host) +
 		" " +
@@ -225,16 +315,69 @@ func (meta Metadata) String() string {
 	return "[" + meta.ID.String() + params + "]"
 }
 
+// mergeMetadata returns base with extra merged in, without modifying either.
+// SD-ELEMENTs sharing an SD-ID are combined since RFC 5424 forbids duplicates,
+// and params of extra replace the params of base with the same name.
+func mergeMetadata(base, extra []Metadata) []Metadata {
+	if len(extra) == 0 {
+		return base
+	}
+
+	merged := make([]Metadata, len(base), len(base)+len(extra))
+	copy(merged, base)
+
+	for _, meta := range extra {
+		i := 0
+		for i < len(merged) && merged[i].ID != meta.ID {
+			i++
+		}
+		if i == len(merged) {
+			merged = append(merged, NewMetadata(meta.ID, meta.Params...))
+			continue
+		}
+
+		params := make([]MetadataParam, 0, len(merged[i].Params)+len(meta.Params))
+		for _, param := range merged[i].Params {
+			overridden := false
+			for _, p := range meta.Params {
+				if p.Name == param.Name {
+					overridden = true
+					break
+				}
+			}
+			if !overridden {
+				params = append(params, param)
+			}
+		}
+		merged[i] = NewMetadata(meta.ID, append(params, meta.Params...)...)
+	}
+
+	return merged
+}
+
 type Message struct {
 	Header   Header
 	Metadata []Metadata
 	Message  []any
 }
 
+func (msg *Message) text() string {
+	message := make([]string, len(msg.Message))
+	for i, msg := range msg.Message {
+		message[i] = fmt.Sprint(msg)
+	}
+	return strings.Join(message, " ")
+}
+
 func (msg *Message) String() string {
+	return msg.string(msg.Header.String())
+}
+
+// string renders msg with the HEADER already formatted.
+func (msg *Message) string(header string) string {
 	message := ""
-	for _, msg := range msg.Message {
-		message += " " + fmt.Sprint(msg)
+	if len(msg.Message) > 0 {
+		message = " " + msg.text()
 	}
 
 	metadata := ""
@@ -246,7 +389,7 @@ func (msg *Message) String() string {
 		metadata = "-"
 	}
 
-	return msg.Header.String() +
+	return header +
 		" " +
 		metadata +
 		message
@@ -264,15 +407,24 @@ type Writer interface {
 	Write(*Message) error
 }
 
-type stdWriter struct{}
+type stdWriter struct {
+	formatter Formatter
+}
 
 func (w stdWriter) Write(msg *Message) error {
-	_, err := fmt.Fprintln(os.Stdout, msg)
+	data, err := format(w.formatter, msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(append(data, '\n'))
 	return err
 }
 
 func NewStderrWriter() Writer {
-	return stdWriter{}
+	return stdWriter{
+		formatter: RFC5424Formatter{},
+	}
 }
 
 type Log struct {
@@ -281,11 +433,19 @@ type Log struct {
 	AppName  option.Option[AppName]
 	HostName option.Option[HostName]
 	Proccess option.Option[ProcessID]
-	Metadata []Metadata
-	Writer   Writer
+	// MessageID is used when a message does not set its own.
+	MessageID option.Option[MessageID]
+	Metadata  []Metadata
+	Writer    Writer
+	// Level is the least severe Severity that is written. Nil writes everything.
+	Level Leveler
 }
 
 func NewDefaultLogger(app option.Option[AppName], host option.Option[HostName], proc option.Option[ProcessID]) Logger {
+	return newDefaultLog(app, host, proc)
+}
+
+func newDefaultLog(app option.Option[AppName], host option.Option[HostName], proc option.Option[ProcessID]) *Log {
 	return &Log{
 		Facility: FacilityUserLevelMessages,
 		Version:  1,
@@ -293,11 +453,54 @@ func NewDefaultLogger(app option.Option[AppName], host option.Option[HostName],
 		HostName: host,
 		Proccess: proc,
 		Metadata: []Metadata{},
-		Writer:   stdWriter{},
+		Writer: stdWriter{
+			formatter: defaultFormatter(),
+		},
 	}
 }
 
+func (log *Log) Enabled(severity Severity) bool {
+	return log.Level == nil || severity <= log.Level.Level()
+}
+
 func (log *Log) write(severity Severity, msg []any) error {
+	if !log.Enabled(severity) {
+		return nil
+	}
+
+	// leading MessageID and Metadata arguments apply to this message only.
+	msgID := log.MessageID
+	var metadata []Metadata
+	i := 0
+loop:
+	for ; i < len(msg); i++ {
+		switch v := msg[i].(type) {
+		case MessageID:
+			msgID = option.Some(v)
+		case Metadata:
+			metadata = append(metadata, v)
+		case []Metadata:
+			metadata = append(metadata, v...)
+		default:
+			break loop
+		}
+	}
+
+	// copy msg since the caller may reuse its variadic slice. The elements are kept by
+	// the Writer, so non-constant arguments are boxed on the heap by the caller even when disabled.
+	return log.send(severity, msgID, mergeMetadata(log.Metadata, metadata), append([]any(nil), msg[i:]...))
+}
+
+// writef formats only when severity is enabled.
+func (log *Log) writef(severity Severity, format string, args ...any) error {
+	if !log.Enabled(severity) {
+		return nil
+	}
+
+	return log.send(severity, log.MessageID, log.Metadata, []any{fmt.Sprintf(format, args...)})
+}
+
+func (log *Log) send(severity Severity, msgID option.Option[MessageID], metadata []Metadata, msg []any) error {
 	return log.Writer.Write(NewMessage(
 		NewHeader(
 			NewPriority(log.Facility, severity),
@@ -306,13 +509,32 @@ func (log *Log) write(severity Severity, msg []any) error {
 			log.HostName,
 			log.AppName,
 			log.Proccess,
-			option.None[MessageID](),
+			msgID,
 		),
-		log.Metadata,
+		metadata,
 		msg...,
 	))
 }
 
+// With returns a Log sharing the Writer and Level with extra Metadata merged into log.Metadata.
+func (log *Log) With(meta ...Metadata) Logger {
+	child := *log
+	child.Metadata = mergeMetadata(log.Metadata, meta)
+	return &child
+}
+
+func (log *Log) WithMessageID(id MessageID) Logger {
+	child := *log
+	child.MessageID = option.Some(id)
+	return &child
+}
+
+func (log *Log) WithApp(app AppName) Logger {
+	child := *log
+	child.AppName = option.Some(app)
+	return &child
+}
+
 func (log *Log) Emergency(msg ...any) error {
 	return log.write(SeverityEmergency, msg)
 }
@@ -345,7 +567,41 @@ func (log *Log) Debug(msg ...any) error {
 	return log.write(SeverityDebug, msg)
 }
 
-var std = func() Logger {
+func (log *Log) Emergencyf(format string, args ...any) error {
+	return log.writef(SeverityEmergency, format, args...)
+}
+
+func (log *Log) Alertf(format string, args ...any) error {
+	return log.writef(SeverityAlert, format, args...)
+}
+
+func (log *Log) Criticalf(format string, args ...any) error {
+	return log.writef(SeverityCritical, format, args...)
+}
+
+func (log *Log) Errorf(format string, args ...any) error {
+	return log.writef(SeverityError, format, args...)
+}
+
+func (log *Log) Warningf(format string, args ...any) error {
+	return log.writef(SeverityWarning, format, args...)
+}
+
+func (log *Log) Noticef(format string, args ...any) error {
+	return log.writef(SeverityNotice, format, args...)
+}
+
+func (log *Log) Infof(format string, args ...any) error {
+	return log.writef(SeverityInformational, format, args...)
+}
+
+func (log *Log) Debugf(format string, args ...any) error {
+	return log.writef(SeverityDebug, format, args...)
+}
+
+var stdLevel = &LevelVar{}
+
+var std = func() *Log {
 	app := func() option.Option[AppName] {
 		exec, err := os.Executable()
 		if err != nil {
@@ -364,11 +620,13 @@ var std = func() Logger {
 
 	pid := option.Some(ProcessID(strconv.Itoa(os.Getpid())))
 
-	return NewDefaultLogger(
+	log := newDefaultLog(
 		app,
 		host,
 		pid,
 	)
+	log.Level = stdLevel
+	return log
 }()
 
 func Emergency(msg ...any) error { return std.Emergency(msg...) }
@@ -386,3 +644,27 @@ func Notice(msg ...any) error { return std.Notice(msg...) }
 func Info(msg ...any) error { return std.Info(msg...) }
 
 func Debug(msg ...any) error { return std.Debug(msg...) }
+
+func Emergencyf(format string, args ...any) error { return std.Emergencyf(format, args...) }
+
+func Alertf(format string, args ...any) error { return std.Alertf(format, args...) }
+
+func Criticalf(format string, args ...any) error { return std.Criticalf(format, args...) }
+
+func Errorf(format string, args ...any) error { return std.Errorf(format, args...) }
+
+func Warningf(format string, args ...any) error { return std.Warningf(format, args...) }
+
+func Noticef(format string, args ...any) error { return std.Noticef(format, args...) }
+
+func Infof(format string, args ...any) error { return std.Infof(format, args...) }
+
+func Debugf(format string, args ...any) error { return std.Debugf(format, args...) }
+
+func Enabled(severity Severity) bool { return std.Enabled(severity) }
+
+// SetLevel sets the Level of the package-level logger.
+func SetLevel(severity Severity) { stdLevel.Set(severity) }
+
+// DefaultLevel returns the Level of the package-level logger, e.g. to register it with a LevelHandler.
+func DefaultLevel() *LevelVar { return stdLevel }
@@ -1,7 +1,9 @@
 package log
 
 import (
+	"fmt"
 	"github.com/a-skua/busybox-go/option"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -353,6 +355,19 @@ func TestHeader_String(t *testing.T) {
 			),
 			want: "<165>1 2023-02-15T12:31:56Z - - - -",
 		},
+		{
+			name: "true: with nanosecond timestamp",
+			header: NewHeader(
+				NewPriority(FacilityLocalUse4, SeverityNotice),
+				1,
+				option.Some(Timestamp(time.Date(2023, 2, 15, 12, 31, 56, 123456789, time.FixedZone("", -7*60*60)))),
+				option.None[HostName](),
+				option.None[AppName](),
+				option.None[ProcessID](),
+				option.None[MessageID](),
+			),
+			want: "<165>1 2023-02-15T12:31:56.123456-07:00 - - - -",
+		},
 		{
 			name: "true: with hostname",
 			header: NewHeader(
@@ -749,3 +764,319 @@ func ExampleLogger() {
 	Info("hello, syslog!")
 	Debug("hello, syslog!")
 }
+
+type messageWriter struct {
+	messages []*Message
+}
+
+func (w *messageWriter) Write(msg *Message) error {
+	w.messages = append(w.messages, msg)
+	return nil
+}
+
+func TestLog_Enabled(t *testing.T) {
+	type test struct {
+		name     string
+		level    Leveler
+		severity Severity
+		want     bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &messageWriter{}
+			log := &Log{Writer: w, Level: tt.level}
+			if got := log.Enabled(tt.severity); tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+			log.write(tt.severity, []any{"hello, syslog!"})
+			if got := len(w.messages) == 1; tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "no level",
+			level:    nil,
+			severity: SeverityDebug,
+			want:     true,
+		},
+		{
+			name:     "more severe",
+			level:    SeverityWarning,
+			severity: SeverityError,
+			want:     true,
+		},
+		{
+			name:     "same severity",
+			level:    SeverityWarning,
+			severity: SeverityWarning,
+			want:     true,
+		},
+		{
+			name:     "less severe",
+			level:    SeverityWarning,
+			severity: SeverityNotice,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestLog_disabledAllocs(t *testing.T) {
+	log := &Log{Writer: &messageWriter{}, Level: SeverityInformational}
+	// neither constant nor served by the runtime's static small-integer table.
+	text := string([]byte("hello, syslog!"))
+	n := 123456
+	// boxing into any is done by the caller; only the cost of Log is measured.
+	args := []any{text, n}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		log.Debug(args...)
+	})
+	if allocs != 0 {
+		t.Fatalf("want=%v, got=%v.", 0, allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		if log.Enabled(SeverityDebug) {
+			log.Debug(text, n)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("want=%v, got=%v.", 0, allocs)
+	}
+}
+
+type countingStringer struct {
+	calls *int
+}
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "expensive"
+}
+
+func TestLog_printf(t *testing.T) {
+	type test struct {
+		name     string
+		logf     func(log *Log) func(format string, args ...any) error
+		severity Severity
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &messageWriter{}
+			log := &Log{Writer: w}
+			if err := tt.logf(log)("%s=%d", "id", 42); err != nil {
+				t.Fatal(err)
+			}
+			if len(w.messages) != 1 {
+				t.Fatalf("want=%v, got=%v.", 1, len(w.messages))
+			}
+			got := w.messages[0]
+			if tt.severity != got.Header.Priority.Severity {
+				t.Fatalf("want=%v, got=%v.", tt.severity, got.Header.Priority.Severity)
+			}
+			if want := "id=42"; want != got.text() {
+				t.Fatalf("want=%v, got=%v.", want, got.text())
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "Emergencyf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Emergencyf },
+			severity: SeverityEmergency,
+		},
+		{
+			name:     "Alertf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Alertf },
+			severity: SeverityAlert,
+		},
+		{
+			name:     "Criticalf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Criticalf },
+			severity: SeverityCritical,
+		},
+		{
+			name:     "Errorf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Errorf },
+			severity: SeverityError,
+		},
+		{
+			name:     "Warningf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Warningf },
+			severity: SeverityWarning,
+		},
+		{
+			name:     "Noticef",
+			logf:     func(log *Log) func(string, ...any) error { return log.Noticef },
+			severity: SeverityNotice,
+		},
+		{
+			name:     "Infof",
+			logf:     func(log *Log) func(string, ...any) error { return log.Infof },
+			severity: SeverityInformational,
+		},
+		{
+			name:     "Debugf",
+			logf:     func(log *Log) func(string, ...any) error { return log.Debugf },
+			severity: SeverityDebug,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestLog_printfLazy(t *testing.T) {
+	calls := 0
+	log := &Log{Writer: &messageWriter{}, Level: SeverityInformational}
+
+	log.Debugf("%v", countingStringer{&calls})
+	if calls != 0 {
+		t.Fatalf("want=%v, got=%v.", 0, calls)
+	}
+
+	log.Infof("%v", countingStringer{&calls})
+	if calls != 1 {
+		t.Fatalf("want=%v, got=%v.", 1, calls)
+	}
+}
+
+func TestMergeMetadata(t *testing.T) {
+	type test struct {
+		name  string
+		base  []Metadata
+		extra []Metadata
+		want  []Metadata
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			before := fmt.Sprint(tt.base)
+			got := mergeMetadata(tt.base, tt.extra)
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+			if after := fmt.Sprint(tt.base); before != after {
+				t.Fatalf("want=%v, got=%v.", before, after)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "no extra",
+			base: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+			want: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+		},
+		{
+			name:  "distinct SD-IDs",
+			base:  []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+			extra: []Metadata{NewMetadata("req@1", NewMetadataParam("id", "abc"))},
+			want: []Metadata{
+				NewMetadata("app@1", NewMetadataParam("version", "1.0")),
+				NewMetadata("req@1", NewMetadataParam("id", "abc")),
+			},
+		},
+		{
+			name: "same SD-ID",
+			base: []Metadata{
+				NewMetadata("app@1", NewMetadataParam("version", "1.0"), NewMetadataParam("env", "prod")),
+			},
+			extra: []Metadata{
+				NewMetadata("app@1", NewMetadataParam("version", "2.0"), NewMetadataParam("region", "jp")),
+			},
+			want: []Metadata{
+				NewMetadata("app@1", NewMetadataParam("env", "prod"), NewMetadataParam("version", "2.0"), NewMetadataParam("region", "jp")),
+			},
+		},
+		{
+			name: "same SD-ID twice in extra",
+			extra: []Metadata{
+				NewMetadata("req@1", NewMetadataParam("id", "abc")),
+				NewMetadata("req@1", NewMetadataParam("user", "alice")),
+			},
+			want: []Metadata{
+				NewMetadata("req@1", NewMetadataParam("id", "abc"), NewMetadataParam("user", "alice")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestLog_perMessage(t *testing.T) {
+	w := &messageWriter{}
+	log := &Log{
+		Facility: FacilityUserLevelMessages,
+		Version:  1,
+		Metadata: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+		Writer:   w,
+	}
+
+	log.Info(MessageID("LOGIN"), NewMetadata("req@1", NewMetadataParam("id", "abc")), "user", "logged in")
+	log.Info("no", "extra")
+
+	if len(w.messages) != 2 {
+		t.Fatalf("want=%v, got=%v.", 2, len(w.messages))
+	}
+	msg := w.messages[0]
+	msg.Header.Timestamp = option.None[Timestamp]()
+	if want := `<14>1 - - - - LOGIN [app@1 version="1.0"][req@1 id="abc"] user logged in`; want != msg.String() {
+		t.Fatalf("want=%v, got=%v.", want, msg.String())
+	}
+	msg = w.messages[1]
+	msg.Header.Timestamp = option.None[Timestamp]()
+	if want := `<14>1 - - - - - [app@1 version="1.0"] no extra`; want != msg.String() {
+		t.Fatalf("want=%v, got=%v.", want, msg.String())
+	}
+}
+
+func TestLog_With(t *testing.T) {
+	w := &messageWriter{}
+	parent := &Log{
+		Facility: FacilityUserLevelMessages,
+		Version:  1,
+		AppName:  option.Some(AppName("busybox")),
+		Metadata: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+		Writer:   w,
+		Level:    SeverityInformational,
+	}
+
+	child := parent.
+		With(NewMetadata("req@1", NewMetadataParam("id", "abc")), NewMetadata("app@1", NewMetadataParam("version", "2.0"))).
+		WithMessageID("HTTP").
+		WithApp("api")
+
+	child.Info("child")
+	child.Info(MessageID("LOGIN"), "override")
+	child.Debug("filtered")
+	parent.Info("parent")
+
+	want := []string{
+		`<14>1 - - api - HTTP [app@1 version="2.0"][req@1 id="abc"] child`,
+		`<14>1 - - api - LOGIN [app@1 version="2.0"][req@1 id="abc"] override`,
+		`<14>1 - - busybox - - [app@1 version="1.0"] parent`,
+	}
+	if len(want) != len(w.messages) {
+		t.Fatalf("want=%v, got=%v.", len(want), len(w.messages))
+	}
+	for i, msg := range w.messages {
+		msg.Header.Timestamp = option.None[Timestamp]()
+		if want[i] != msg.String() {
+			t.Fatalf("want=%v, got=%v.", want[i], msg.String())
+		}
+	}
+}
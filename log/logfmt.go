@@ -0,0 +1,68 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LogfmtFormatter renders header fields as keys and SD-PARAMs as "sdid.name" keys.
+type LogfmtFormatter struct{}
+
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+	return value
+}
+
+type logfmtBuilder struct {
+	strings.Builder
+}
+
+func (b *logfmtBuilder) pair(key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(logfmtValue(value))
+}
+
+func (f LogfmtFormatter) Format(msg *Message) ([]byte, error) {
+	b := &logfmtBuilder{}
+	h := msg.Header
+
+	if h.Timestamp.Valid {
+		b.pair("time", h.Timestamp.Value.String())
+	}
+	b.pair("facility", h.Priority.Facility.String())
+	b.pair("severity", h.Priority.Severity.String())
+	if h.Host.Valid {
+		b.pair("host", h.Host.Value.String())
+	}
+	if h.App.Valid {
+		b.pair("app", h.App.Value.String())
+	}
+	if h.ProcessID.Valid {
+		b.pair("procid", h.ProcessID.Value.String())
+	}
+	if h.MessageID.Valid {
+		b.pair("msgid", h.MessageID.Value.String())
+	}
+	b.pair("msg", msg.text())
+
+	for _, meta := range msg.Metadata {
+		for _, param := range meta.Params {
+			b.pair(meta.ID.String()+"."+param.Name.String(), param.Value.String())
+		}
+	}
+
+	return []byte(b.String()), nil
+}
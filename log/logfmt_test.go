@@ -0,0 +1,118 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	"testing"
+	"time"
+)
+
+func TestLogfmtValue(t *testing.T) {
+	type test struct {
+		name  string
+		value string
+		want  string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got := logfmtValue(tt.value)
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:  "bare",
+			value: "busybox",
+			want:  "busybox",
+		},
+		{
+			name:  "empty",
+			value: "",
+			want:  `""`,
+		},
+		{
+			name:  "space",
+			value: "hello, syslog!",
+			want:  `"hello, syslog!"`,
+		},
+		{
+			name:  "equals",
+			value: "a=b",
+			want:  `"a=b"`,
+		},
+		{
+			name:  "quote",
+			value: `say "hi"`,
+			want:  `"say \"hi\""`,
+		},
+		{
+			name:  "newline",
+			value: "a\nb",
+			want:  `"a\nb"`,
+		},
+		{
+			name:  "unicode",
+			value: "こんにちは",
+			want:  "こんにちは",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestLogfmtFormatter_Format(t *testing.T) {
+	type test struct {
+		name    string
+		message *Message
+		want    string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LogfmtFormatter{}.Format(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != string(got) {
+				t.Fatalf("want=%v, got=%s.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:    "minimum",
+			message: newTestMessage(),
+			want:    `facility=local4 severity=notice msg=""`,
+		},
+		{
+			name: "full",
+			message: NewMessage(
+				NewHeader(
+					NewPriority(FacilityUserLevelMessages, SeverityError),
+					1,
+					option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC))),
+					option.Some(HostName("localhost")),
+					option.Some(AppName("busybox")),
+					option.Some(ProcessID("1234")),
+					option.Some(MessageID("ID47")),
+				),
+				[]Metadata{
+					NewMetadata("exampleSDID@1", NewMetadataParam("eventID", "1011"), NewMetadataParam("eventSource", "Application Log")),
+					NewMetadata("exampleSDID@0"),
+				},
+				"hello, syslog!",
+			),
+			want: `time=2023-02-16T12:34:56Z facility=user severity=error host=localhost app=busybox procid=1234 msgid=ID47 msg="hello, syslog!" exampleSDID@1.eventID=1011 exampleSDID@1.eventSource="Application Log"`,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
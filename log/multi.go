@@ -0,0 +1,92 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/a-skua/busybox-go/option"
+)
+
+// Destination is one Writer of a MultiWriter with the Messages routed to it.
+// The zero value besides Writer delivers every Message.
+type Destination struct {
+	Writer Writer
+	// MostSevere and LeastSevere bound the delivered severities, inclusive.
+	// None leaves that side unbounded, so "Error and above" is LeastSevere Some(SeverityError).
+	MostSevere  option.Option[Severity]
+	LeastSevere option.Option[Severity]
+	// Facilities restricts the delivered facilities. Empty means all.
+	Facilities []Facility
+}
+
+// NewDestination routes every Message to w.
+func NewDestination(w Writer) *Destination {
+	return &Destination{
+		Writer: w,
+	}
+}
+
+func (d *Destination) accept(pri Priority) bool {
+	if d.MostSevere.Valid && pri.Severity < d.MostSevere.Value {
+		return false
+	}
+	if d.LeastSevere.Valid && pri.Severity > d.LeastSevere.Value {
+		return false
+	}
+	if len(d.Facilities) == 0 {
+		return true
+	}
+	for _, f := range d.Facilities {
+		if f == pri.Facility {
+			return true
+		}
+	}
+	return false
+}
+
+// MultiWriter delivers each Message to every Destination accepting it.
+type MultiWriter struct {
+	destinations []*Destination
+}
+
+func NewMultiWriter(destinations ...*Destination) *MultiWriter {
+	return &MultiWriter{
+		destinations: destinations,
+	}
+}
+
+// Write continues past failing destinations and returns their errors joined.
+func (w *MultiWriter) Write(msg *Message) error {
+	var errs []error
+	for i, d := range w.destinations {
+		if !d.accept(msg.Header.Priority) {
+			continue
+		}
+		if err := d.Writer.Write(msg); err != nil {
+			errs = append(errs, fmt.Errorf("log: destination %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *MultiWriter) Flush(ctx context.Context) error {
+	return w.each(func(d *Destination) error {
+		return FlushWriter(ctx, d.Writer)
+	})
+}
+
+func (w *MultiWriter) Close(ctx context.Context) error {
+	return w.each(func(d *Destination) error {
+		return CloseWriter(ctx, d.Writer)
+	})
+}
+
+func (w *MultiWriter) each(f func(*Destination) error) error {
+	var errs []error
+	for i, d := range w.destinations {
+		if err := f(d); err != nil {
+			errs = append(errs, fmt.Errorf("log: destination %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
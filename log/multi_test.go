@@ -0,0 +1,106 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+	"testing"
+)
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write(*Message) error {
+	return w.err
+}
+
+func TestMultiWriter_Write(t *testing.T) {
+	type test struct {
+		name     string
+		priority Priority
+		want     [3]int
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			relay, file, stdout := &messageWriter{}, &messageWriter{}, &messageWriter{}
+
+			errorsOnly := NewDestination(relay)
+			errorsOnly.LeastSevere = option.Some(SeverityError)
+			debugOnly := NewDestination(stdout)
+			debugOnly.MostSevere = option.Some(SeverityDebug)
+			debugOnly.Facilities = []Facility{FacilityLocalUse4}
+
+			// the zero value delivers everything.
+			w := NewMultiWriter(errorsOnly, &Destination{Writer: file}, debugOnly)
+			msg := newTestMessage("foo")
+			msg.Header.Priority = tt.priority
+			if err := w.Write(msg); err != nil {
+				t.Fatal(err)
+			}
+
+			got := [3]int{len(relay.messages), len(file.messages), len(stdout.messages)}
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "error",
+			priority: NewPriority(FacilityLocalUse4, SeverityError),
+			want:     [3]int{1, 1, 0},
+		},
+		{
+			name:     "info",
+			priority: NewPriority(FacilityLocalUse4, SeverityInformational),
+			want:     [3]int{0, 1, 0},
+		},
+		{
+			name:     "debug",
+			priority: NewPriority(FacilityLocalUse4, SeverityDebug),
+			want:     [3]int{0, 1, 1},
+		},
+		{
+			name:     "debug of other facility",
+			priority: NewPriority(FacilityUserLevelMessages, SeverityDebug),
+			want:     [3]int{0, 1, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestMultiWriter_errors(t *testing.T) {
+	errFoo := errors.New("foo")
+	errBar := errors.New("bar")
+	ok := &lifecycleWriter{flushErr: errBar}
+
+	w := NewMultiWriter(
+		NewDestination(errWriter{errFoo}),
+		NewDestination(ok),
+		NewDestination(errWriter{errBar}),
+	)
+
+	err := w.Write(newTestMessage("foo"))
+	if !errors.Is(err, errFoo) || !errors.Is(err, errBar) {
+		t.Fatalf("want-error=%v, error=%v.", errors.Join(errFoo, errBar), err)
+	}
+	if len(ok.messages) != 1 {
+		t.Fatalf("want=%v, got=%v.", 1, len(ok.messages))
+	}
+
+	if err := w.Flush(context.Background()); !errors.Is(err, errBar) {
+		t.Fatalf("want-error=%v, error=%v.", errBar, err)
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ok.flushed != 1 || ok.closed != 1 {
+		t.Fatalf("want=%v, got=%v.", [2]int{1, 1}, [2]int{ok.flushed, ok.closed})
+	}
+}
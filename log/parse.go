@@ -0,0 +1,305 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	"strconv"
+	"time"
+)
+
+type ParseError struct {
+	Offset int
+	Msg    string
+}
+
+func (err *ParseError) Error() string {
+	return "log: parse error at offset " + strconv.Itoa(err.Offset) + ": " + err.Msg
+}
+
+const nilValue = '-'
+
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func (p *parser) errorf(msg string) error {
+	return &ParseError{
+		Offset: p.pos,
+		Msg:    msg,
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	if p.eof() {
+		return p.errorf("expected " + strconv.QuoteRune(rune(c)) + ", got end of message")
+	}
+	if p.peek() != c {
+		return p.errorf("expected " + strconv.QuoteRune(rune(c)) + ", got " + strconv.QuoteRune(rune(p.peek())))
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) digits(max int) (int, error) {
+	start := p.pos
+	for !p.eof() && p.pos-start < max && '0' <= p.peek() && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, p.errorf("expected digit")
+	}
+	return strconv.Atoi(string(p.data[start:p.pos]))
+}
+
+// token reads 1*PRINTUSASCII up to the next SP.
+func (p *parser) token(field string) (string, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != ' ' {
+		if c := p.peek(); c < 33 || c > 126 {
+			return "", p.errorf("invalid character in " + field)
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("empty " + field)
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+func parseField[T ~string](p *parser, field string) (option.Option[T], error) {
+	token, err := p.token(field)
+	if err != nil {
+		return option.None[T](), err
+	}
+	if token == string(nilValue) {
+		return option.None[T](), nil
+	}
+	return option.Some(T(token)), nil
+}
+
+func (p *parser) priority() (Priority, error) {
+	if err := p.expect('<'); err != nil {
+		return Priority{}, err
+	}
+	start := p.pos
+	num, err := p.digits(3)
+	if err != nil {
+		return Priority{}, err
+	}
+	if num > 191 || (p.pos-start > 1 && p.data[start] == '0') {
+		p.pos = start
+		return Priority{}, p.errorf("invalid PRI value")
+	}
+	if err := p.expect('>'); err != nil {
+		return Priority{}, err
+	}
+	return NewPriority(Facility(num/8), Severity(num%8)), nil
+}
+
+func (p *parser) version() (Version, error) {
+	start := p.pos
+	num, err := p.digits(3)
+	if err != nil {
+		return 0, err
+	}
+	if num == 0 || num > 255 || p.data[start] == '0' {
+		p.pos = start
+		return 0, p.errorf("invalid VERSION")
+	}
+	return Version(num), nil
+}
+
+func (p *parser) timestamp() (option.Option[Timestamp], error) {
+	start := p.pos
+	token, err := p.token("TIMESTAMP")
+	if err != nil {
+		return option.None[Timestamp](), err
+	}
+	if token == string(nilValue) {
+		return option.None[Timestamp](), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, token)
+	if err != nil {
+		p.pos = start
+		return option.None[Timestamp](), p.errorf("invalid TIMESTAMP")
+	}
+	return option.Some(Timestamp(t)), nil
+}
+
+func (p *parser) header() (Header, error) {
+	var h Header
+	var err error
+
+	if h.Priority, err = p.priority(); err != nil {
+		return h, err
+	}
+	if h.Version, err = p.version(); err != nil {
+		return h, err
+	}
+	if err = p.expect(' '); err != nil {
+		return h, err
+	}
+	if h.Timestamp, err = p.timestamp(); err != nil {
+		return h, err
+	}
+	if err = p.expect(' '); err != nil {
+		return h, err
+	}
+	if h.Host, err = parseField[HostName](p, "HOSTNAME"); err != nil {
+		return h, err
+	}
+	if err = p.expect(' '); err != nil {
+		return h, err
+	}
+	if h.App, err = parseField[AppName](p, "APP-NAME"); err != nil {
+		return h, err
+	}
+	if err = p.expect(' '); err != nil {
+		return h, err
+	}
+	if h.ProcessID, err = parseField[ProcessID](p, "PROCID"); err != nil {
+		return h, err
+	}
+	if err = p.expect(' '); err != nil {
+		return h, err
+	}
+	if h.MessageID, err = parseField[MessageID](p, "MSGID"); err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
+// name reads an SD-NAME: 1*32 PRINTUSASCII except '=', SP, ']' and '"'.
+func (p *parser) name(field string) (string, error) {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '=' || c == ' ' || c == ']' || c == '"' {
+			break
+		}
+		if c < 33 || c > 126 {
+			return "", p.errorf("invalid character in " + field)
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("empty " + field)
+	}
+	if p.pos-start > 32 {
+		p.pos = start
+		return "", p.errorf(field + " is longer than 32 characters")
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+func (p *parser) paramValue() (MetadataValue, error) {
+	if err := p.expect('"'); err != nil {
+		return "", err
+	}
+	value := make([]byte, 0, 16)
+	for !p.eof() {
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return MetadataValue(value), nil
+		case '\\':
+			p.pos++
+			if !p.eof() {
+				switch p.peek() {
+				case '"', '\\', ']':
+					c = p.peek()
+					p.pos++
+				}
+			}
+			value = append(value, c)
+		case ']':
+			return "", p.errorf("unescaped ']' in PARAM-VALUE")
+		default:
+			value = append(value, c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated PARAM-VALUE")
+}
+
+func (p *parser) element() (Metadata, error) {
+	if err := p.expect('['); err != nil {
+		return Metadata{}, err
+	}
+	id, err := p.name("SD-ID")
+	if err != nil {
+		return Metadata{}, err
+	}
+	meta := NewMetadata(MetadataID(id))
+	for !p.eof() && p.peek() == ' ' {
+		p.pos++
+		name, err := p.name("PARAM-NAME")
+		if err != nil {
+			return Metadata{}, err
+		}
+		if err := p.expect('='); err != nil {
+			return Metadata{}, err
+		}
+		value, err := p.paramValue()
+		if err != nil {
+			return Metadata{}, err
+		}
+		meta.Params = append(meta.Params, NewMetadataParam(MetadataName(name), value))
+	}
+	if err := p.expect(']'); err != nil {
+		return Metadata{}, err
+	}
+	return meta, nil
+}
+
+func (p *parser) structuredData() ([]Metadata, error) {
+	if !p.eof() && p.peek() == nilValue {
+		p.pos++
+		return nil, nil
+	}
+	var metadata []Metadata
+	for {
+		meta, err := p.element()
+		if err != nil {
+			return nil, err
+		}
+		metadata = append(metadata, meta)
+		if p.eof() || p.peek() != '[' {
+			return metadata, nil
+		}
+	}
+}
+
+// ParseMessage parses an RFC 5424 SYSLOG-MSG as produced by Message.String.
+// The MSG part, if any, is stored as a single string in Message.Message.
+func ParseMessage(data []byte) (*Message, error) {
+	p := &parser{data: data}
+
+	head, err := p.header()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(' '); err != nil {
+		return nil, err
+	}
+	meta, err := p.structuredData()
+	if err != nil {
+		return nil, err
+	}
+	if p.eof() {
+		return NewMessage(head, meta), nil
+	}
+	if err := p.expect(' '); err != nil {
+		return nil, err
+	}
+	return NewMessage(head, meta, string(p.data[p.pos:])), nil
+}
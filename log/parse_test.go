@@ -0,0 +1,199 @@
+package log
+
+import (
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMessage(t *testing.T) {
+	type test struct {
+		name    string
+		data    string
+		want    *Message
+		wantErr bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMessage([]byte(tt.data))
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.want == nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "minimum",
+			data: "<165>1 - - - - - -",
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityLocalUse4, SeverityNotice),
+					1,
+					option.None[Timestamp](),
+					option.None[HostName](),
+					option.None[AppName](),
+					option.None[ProcessID](),
+					option.None[MessageID](),
+				),
+				nil,
+			),
+		},
+		{
+			name: "with header and message",
+			data: "<165>1 2023-02-16T12:34:56Z localhost busybox 1234 ID47 - hello, syslog!",
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityLocalUse4, SeverityNotice),
+					1,
+					option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 0, time.UTC))),
+					option.Some(HostName("localhost")),
+					option.Some(AppName("busybox")),
+					option.Some(ProcessID("1234")),
+					option.Some(MessageID("ID47")),
+				),
+				nil,
+				"hello, syslog!",
+			),
+		},
+		{
+			name: "with metadata",
+			data: `<165>1 - - - - - [exampleSDID@0][exampleSDID@1 eventID="1011" eventSource="Application"] hello`,
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityLocalUse4, SeverityNotice),
+					1,
+					option.None[Timestamp](),
+					option.None[HostName](),
+					option.None[AppName](),
+					option.None[ProcessID](),
+					option.None[MessageID](),
+				),
+				[]Metadata{
+					NewMetadata("exampleSDID@0"),
+					NewMetadata("exampleSDID@1", NewMetadataParam("eventID", "1011"), NewMetadataParam("eventSource", "Application")),
+				},
+				"hello",
+			),
+		},
+		{
+			name: "unescape param value",
+			data: `<165>1 - - - - - [id@0 v="a\"b\\c\]d\e"]`,
+			want: NewMessage(
+				NewHeader(
+					NewPriority(FacilityLocalUse4, SeverityNotice),
+					1,
+					option.None[Timestamp](),
+					option.None[HostName](),
+					option.None[AppName](),
+					option.None[ProcessID](),
+					option.None[MessageID](),
+				),
+				[]Metadata{
+					NewMetadata("id@0", NewMetadataParam("v", `a"b\c]d\e`)),
+				},
+			),
+		},
+		{
+			name:    "invalid priority",
+			data:    "<192>1 - - - - - -",
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			data:    "<165>0 - - - - - -",
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp",
+			data:    "<165>1 yesterday - - - - -",
+			wantErr: true,
+		},
+		{
+			name:    "missing structured data",
+			data:    "<165>1 - - - - -",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated param value",
+			data:    `<165>1 - - - - - [id@0 v="abc]`,
+			wantErr: true,
+		},
+		{
+			name:    "missing space before message",
+			data:    "<165>1 - - - - - -hello",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestParseMessage_roundTrip(t *testing.T) {
+	type test struct {
+		name    string
+		message *Message
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.message.String()
+			got, err := ParseMessage([]byte(want))
+			if err != nil {
+				t.Fatalf("error=%v.", err)
+			}
+			if want != got.String() {
+				t.Fatalf("want=%v, got=%v.", want, got.String())
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "full",
+			message: NewMessage(
+				NewHeader(
+					NewPriority(FacilityUserLevelMessages, SeverityInformational),
+					1,
+					option.Some(Timestamp(time.Date(2023, 02, 16, 12, 34, 56, 123456000, time.FixedZone("JST", 9*60*60)))),
+					option.Some(HostName("localhost")),
+					option.Some(AppName("busybox")),
+					option.Some(ProcessID("42")),
+					option.Some(MessageID("LOGIN")),
+				),
+				[]Metadata{
+					NewMetadata("benchmark@2", NewMetadataParam("foo", "FOO"), NewMetadataParam("bar", `[BAR] "\"`)),
+				},
+				"foo",
+				"bar",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := ParseMessage([]byte("<165>1 - - - - - [id@0 v=x]"))
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("want=*ParseError, got=%T.", err)
+	}
+	if want := 25; want != perr.Offset {
+		t.Fatalf("want=%v, got=%v.", want, perr.Offset)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package log
+
+// NotifyReopenSignal is a no-op on platforms without SIGHUP.
+func NotifyReopenSignal(w *FileWriter) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,37 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// NotifyReopenSignal makes SIGHUP reopen w until stop is called, as expected by
+// logrotate postrotate scripts. stop may be called more than once.
+func NotifyReopenSignal(w *FileWriter) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				w.reopenOnSignal()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
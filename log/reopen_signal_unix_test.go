@@ -0,0 +1,49 @@
+//go:build unix
+
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyReopenSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	stop := NotifyReopenSignal(w)
+	defer stop()
+
+	// logrotate with create: the file is moved, recreated and syslog is signalled.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		w.mu.Lock()
+		info := w.info
+		w.mu.Unlock()
+		if current, err := os.Stat(path); err == nil && os.SameFile(info, current) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("want=%v, got=%v.", "reopened", "not reopened")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// stop is deferred as well.
+	stop()
+}
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RFC3164 The BSD syslog Protocol: https://www.rfc-editor.org/rfc/rfc3164
+const (
+	RFC3164MaxMessageSize = 1024
+	RFC3164MaxTagSize     = 32
+)
+
+type RFC3164Metadata uint8
+
+const (
+	RFC3164DropMetadata RFC3164Metadata = iota
+	// RFC3164FlattenMetadata prepends the SD-ELEMENTs to the CONTENT.
+	RFC3164FlattenMetadata
+)
+
+type RFC3164Formatter struct {
+	Metadata RFC3164Metadata
+	// Location for the TIMESTAMP, which carries neither year nor time zone. Defaults to time.Local.
+	Location *time.Location
+}
+
+func (f RFC3164Formatter) timestamp(t option.Option[Timestamp]) string {
+	ts := time.Now()
+	if t.Valid {
+		ts = time.Time(t.Value)
+	}
+
+	loc := f.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return ts.In(loc).Format(time.Stamp)
+}
+
+// localHostName is the HOSTNAME of Messages without a host, since RFC 3164 has no NILVALUE.
+var localHostName = sync.OnceValue(func() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "-"
+	}
+	return hostname
+})
+
+func rfc3164Host(host option.Option[HostName]) string {
+	if !host.Valid {
+		return localHostName()
+	}
+	return host.Value.String()
+}
+
+func rfc3164Tag(h Header) string {
+	tag := ""
+	if h.App.Valid {
+		tag = filepath.Base(h.App.Value.String())
+		if len(tag) > RFC3164MaxTagSize {
+			tag = string(truncate([]byte(tag), RFC3164MaxTagSize))
+		}
+	}
+	if h.ProcessID.Valid {
+		tag += "[" + h.ProcessID.Value.String() + "]"
+	}
+	return tag
+}
+
+func (f RFC3164Formatter) Format(msg *Message) ([]byte, error) {
+	content := msg.text()
+	if f.Metadata == RFC3164FlattenMetadata && len(msg.Metadata) > 0 {
+		metadata := ""
+		for _, meta := range msg.Metadata {
+			metadata += meta.String()
+		}
+		if content == "" {
+			content = metadata
+		} else {
+			content = metadata + " " + content
+		}
+	}
+
+	if tag := rfc3164Tag(msg.Header); tag != "" {
+		content = tag + ": " + content
+	}
+
+	data := msg.Header.Priority.String() +
+		f.timestamp(msg.Header.Timestamp) +
+		" " +
+		rfc3164Host(msg.Header.Host) +
+		" " +
+		content
+
+	return truncate([]byte(data), RFC3164MaxMessageSize), nil
+}
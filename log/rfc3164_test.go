@@ -0,0 +1,97 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRFC3164Formatter_Format(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	type test struct {
+		name      string
+		formatter RFC3164Formatter
+		message   *Message
+		want      string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.formatter.Format(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != string(got) {
+				t.Fatalf("want=%v, got=%s.", tt.want, got)
+			}
+		})
+	}
+
+	header := NewHeader(
+		NewPriority(FacilityLocalUse4, SeverityNotice),
+		1,
+		option.Some(Timestamp(time.Date(2023, 02, 6, 12, 34, 56, 789000000, time.UTC))),
+		option.Some(HostName("localhost")),
+		option.Some(AppName("/usr/local/bin/busybox")),
+		option.Some(ProcessID("1234")),
+		option.Some(MessageID("ID47")),
+	)
+	metadata := []Metadata{
+		NewMetadata("exampleSDID@1", NewMetadataParam("eventID", "1011")),
+	}
+
+	tests := []*test{
+		{
+			name:      "tag and pid",
+			formatter: RFC3164Formatter{Location: time.UTC},
+			message:   NewMessage(header, metadata, "hello,", "syslog!"),
+			want:      "<165>Feb  6 12:34:56 localhost busybox[1234]: hello, syslog!",
+		},
+		{
+			name:      "flatten metadata",
+			formatter: RFC3164Formatter{Metadata: RFC3164FlattenMetadata, Location: time.UTC},
+			message:   NewMessage(header, metadata, "hello, syslog!"),
+			want:      "<165>Feb  6 12:34:56 localhost busybox[1234]: [exampleSDID@1 eventID=\"1011\"] hello, syslog!",
+		},
+		{
+			name:      "convert location",
+			formatter: RFC3164Formatter{Location: time.FixedZone("JST", 9*60*60)},
+			message:   NewMessage(header, nil, "hello, syslog!"),
+			want:      "<165>Feb  6 21:34:56 localhost busybox[1234]: hello, syslog!",
+		},
+		{
+			name:      "without host and tag",
+			formatter: RFC3164Formatter{Location: time.UTC},
+			message: NewMessage(
+				NewHeader(
+					NewPriority(FacilityUserLevelMessages, SeverityError),
+					1,
+					option.Some(Timestamp(time.Date(2023, 12, 24, 1, 2, 3, 0, time.UTC))),
+					option.None[HostName](),
+					option.None[AppName](),
+					option.None[ProcessID](),
+					option.None[MessageID](),
+				),
+				nil,
+				"hello, syslog!",
+			),
+			want: "<11>Dec 24 01:02:03 " + hostname + " hello, syslog!",
+		},
+		{
+			name:      "truncate",
+			formatter: RFC3164Formatter{Location: time.UTC},
+			message:   NewMessage(header, nil, strings.Repeat("a", 2000)),
+			want:      "<165>Feb  6 12:34:56 localhost busybox[1234]: " + strings.Repeat("a", RFC3164MaxMessageSize-len("<165>Feb  6 12:34:56 localhost busybox[1234]: ")),
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
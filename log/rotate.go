@@ -0,0 +1,408 @@
+package log
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Rotation uint8
+
+const (
+	// RotationNone rotates by size only.
+	RotationNone Rotation = iota
+	RotationHourly
+	RotationDaily
+)
+
+// period returns the start of the rotation period containing t.
+func (r Rotation) period(t time.Time) time.Time {
+	switch r {
+	case RotationHourly:
+		y, m, d := t.Date()
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, t.Location())
+	case RotationDaily:
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// backupTimeFormat is inserted between the base name and the extension of rotated segments,
+// e.g. app-2023-02-16T12-34-56.000.log.gz. Segments rotated within the same millisecond
+// get a sequence number, e.g. app-2023-02-16T12-34-56.000-1.log.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressSuffix = ".gz"
+
+// RotatingFileWriter appends Messages to a file and rotates it by size and time.
+// Rotated segments are renamed next to the file, optionally compressed with gzip
+// and removed by the retention limits in the background.
+type RotatingFileWriter struct {
+	Formatter Formatter
+	// MaxSize rotates the file before it grows beyond MaxSize bytes. Zero means no limit.
+	MaxSize  int64
+	Rotation Rotation
+	Compress bool
+	// MaxBackups, MaxTotalSize and MaxAge limit the rotated segments that are kept.
+	// Zero means no limit.
+	MaxBackups   int
+	MaxTotalSize int64
+	MaxAge       time.Duration
+	// ErrorHandler, if set, is called when a rotation by Write fails, in which case the
+	// Message is written to the current file, and from the background goroutine when
+	// compression or removal fails.
+	ErrorHandler func(error)
+
+	path   string
+	now    func() time.Time
+	rename func(oldpath, newpath string) error
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	period time.Time
+	closed bool
+
+	mill sync.Mutex
+	wg   sync.WaitGroup
+}
+
+func NewRotatingFileWriter(path string) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{
+		path:   path,
+		now:    time.Now,
+		rename: os.Rename,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	// a file left over from an earlier period is rotated by the first Write.
+	w.period = info.ModTime()
+	if w.size == 0 {
+		w.period = w.now()
+	}
+	return nil
+}
+
+func (w *RotatingFileWriter) Write(msg *Message) error {
+	data, err := format(w.Formatter, msg)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	if w.file != nil && w.shouldRotate(int64(len(data))) {
+		if err := w.rotate(); err != nil && w.ErrorHandler != nil {
+			w.ErrorHandler(err)
+		}
+	}
+	// the file is nil when it could not be reopened by an earlier rotation.
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.file.Write(data)
+	w.size += int64(n)
+	return err
+}
+
+func (w *RotatingFileWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.MaxSize > 0 && w.size+n > w.MaxSize {
+		return true
+	}
+	now := w.now()
+	return !w.Rotation.period(now).Equal(w.Rotation.period(w.period.In(now.Location())))
+}
+
+// Rotate rotates the file regardless of its size and age.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	return w.rotate()
+}
+
+// rotate renames the file and opens a new one. The file is reopened even when closing
+// or renaming fails so that writing continues; w.file is nil only if opening fails.
+func (w *RotatingFileWriter) rotate() error {
+	closeErr := w.file.Close()
+	w.file = nil
+
+	renameErr := w.rename(w.path, w.backupName(w.now()))
+	if errors.Is(renameErr, os.ErrNotExist) {
+		renameErr = nil
+	}
+	if err := w.open(); err != nil {
+		return errors.Join(closeErr, renameErr, err)
+	}
+	// a failed rotation is retried by size, or in the next period.
+	w.period = w.now()
+	if renameErr != nil {
+		return errors.Join(closeErr, renameErr)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.millRun()
+	}()
+	return nil
+}
+
+func (w *RotatingFileWriter) prefixAndExt() (string, string) {
+	name := filepath.Base(w.path)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backupName returns a name for a segment rotated at t that does not replace an existing one.
+func (w *RotatingFileWriter) backupName(t time.Time) string {
+	prefix, ext := w.prefixAndExt()
+	base := filepath.Join(filepath.Dir(w.path), prefix+t.UTC().Format(backupTimeFormat))
+	name := base + ext
+	for seq := 1; exists(name) || exists(name+compressSuffix); seq++ {
+		name = base + "-" + strconv.Itoa(seq) + ext
+	}
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// parseBackupStamp parses the time and the optional sequence number of a segment.
+func parseBackupStamp(stamp string) (time.Time, int, error) {
+	if len(stamp) < len(backupTimeFormat) {
+		return time.Time{}, 0, errors.New("log: short backup time")
+	}
+	t, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	rest := stamp[len(backupTimeFormat):]
+	if rest == "" {
+		return t, 0, nil
+	}
+	if rest[0] != '-' {
+		return time.Time{}, 0, errors.New("log: invalid backup sequence")
+	}
+	seq, err := strconv.Atoi(rest[1:])
+	if err != nil || seq < 1 {
+		return time.Time{}, 0, errors.New("log: invalid backup sequence")
+	}
+	return t, seq, nil
+}
+
+type backup struct {
+	path    string
+	time    time.Time
+	seq     int
+	size    int64
+	archive bool
+}
+
+// backups returns the rotated segments, newest first.
+func (w *RotatingFileWriter) backups() ([]backup, error) {
+	dir := filepath.Dir(w.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, ext := w.prefixAndExt()
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		archive := strings.HasSuffix(name, ext+compressSuffix)
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name, compressSuffix), ext)
+		if !strings.HasPrefix(stamp, prefix) || !(archive || strings.HasSuffix(name, ext)) {
+			continue
+		}
+		t, seq, err := parseBackupStamp(strings.TrimPrefix(stamp, prefix))
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{
+			path:    filepath.Join(dir, name),
+			time:    t,
+			seq:     seq,
+			size:    info.Size(),
+			archive: archive,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].time.Equal(backups[j].time) {
+			return backups[i].time.After(backups[j].time)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	return backups, nil
+}
+
+// millRun compresses rotated segments and enforces the retention limits.
+func (w *RotatingFileWriter) millRun() {
+	w.mill.Lock()
+	defer w.mill.Unlock()
+
+	if err := w.millOnce(); err != nil && w.ErrorHandler != nil {
+		w.ErrorHandler(err)
+	}
+}
+
+func (w *RotatingFileWriter) millOnce() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var total int64
+	now := w.now()
+	for i, b := range backups {
+		total += b.size
+		expired := (w.MaxBackups > 0 && i >= w.MaxBackups) ||
+			(w.MaxTotalSize > 0 && total > w.MaxTotalSize) ||
+			(w.MaxAge > 0 && now.Sub(b.time) > w.MaxAge)
+		if expired {
+			if err := os.Remove(b.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if w.Compress && !b.archive {
+			if err := compress(b.path, b.path+compressSuffix); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if info, err := os.Stat(b.path + compressSuffix); err == nil {
+				total += info.Size() - b.size
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compress gzips src into dst and removes src.
+func compress(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// Flush commits the file to stable storage.
+func (w *RotatingFileWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
+}
+
+// Close closes the file and waits for the background compression and removal.
+func (w *RotatingFileWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	var err error
+	if !w.closed && w.file != nil {
+		err = w.file.Close()
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+}
@@ -0,0 +1,352 @@
+package log
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is advanced by the tests instead of sleeping.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestRotatingFileWriter(t *testing.T, clock *fakeClock) (*RotatingFileWriter, string) {
+	dir := t.TempDir()
+	w, err := NewRotatingFileWriter(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.now = clock.now
+	w.period = clock.now()
+	t.Cleanup(func() { w.Close(context.Background()) })
+	return w, dir
+}
+
+func readDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, compressSuffix) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRotatingFileWriter_Write(t *testing.T) {
+	type test struct {
+		name     string
+		maxSize  int64
+		rotation Rotation
+		step     time.Duration
+		want     []string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{t: time.Date(2023, 02, 16, 23, 59, 58, 0, time.UTC)}
+			w, dir := newTestRotatingFileWriter(t, clock)
+			w.MaxSize = tt.maxSize
+			w.Rotation = tt.rotation
+
+			for _, msg := range []string{"foo", "bar", "baz"} {
+				if err := w.Write(newTestMessage(msg)); err != nil {
+					t.Fatal(err)
+				}
+				clock.advance(tt.step)
+			}
+			if err := w.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, name := range readDir(t, dir) {
+				got = append(got, name+": "+readFile(t, filepath.Join(dir, name)))
+			}
+			if strings.Join(tt.want, "") != strings.Join(got, "") {
+				t.Fatalf("want=%q, got=%q.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:    "no rotation",
+			maxSize: 1024,
+			step:    time.Second,
+			want: []string{
+				"app.log: <165>1 - - - - - - foo\n<165>1 - - - - - - bar\n<165>1 - - - - - - baz\n",
+			},
+		},
+		{
+			name:    "size",
+			maxSize: 50,
+			step:    time.Millisecond,
+			want: []string{
+				"app-2023-02-16T23-59-58.002.log: <165>1 - - - - - - foo\n<165>1 - - - - - - bar\n",
+				"app.log: <165>1 - - - - - - baz\n",
+			},
+		},
+		{
+			name:     "daily",
+			rotation: RotationDaily,
+			step:     time.Second,
+			want: []string{
+				"app-2023-02-17T00-00-00.000.log: <165>1 - - - - - - foo\n<165>1 - - - - - - bar\n",
+				"app.log: <165>1 - - - - - - baz\n",
+			},
+		},
+		{
+			name:     "hourly",
+			rotation: RotationHourly,
+			step:     time.Hour,
+			want: []string{
+				"app-2023-02-17T00-59-58.000.log: <165>1 - - - - - - foo\n",
+				"app-2023-02-17T01-59-58.000.log: <165>1 - - - - - - bar\n",
+				"app.log: <165>1 - - - - - - baz\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestRotatingFileWriter_retention(t *testing.T) {
+	type test struct {
+		name         string
+		compress     bool
+		maxBackups   int
+		maxTotalSize int64
+		maxAge       time.Duration
+		want         []string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{t: time.Date(2023, 02, 16, 0, 0, 0, 0, time.UTC)}
+			w, dir := newTestRotatingFileWriter(t, clock)
+			w.Compress = tt.compress
+			w.MaxBackups = tt.maxBackups
+			w.MaxTotalSize = tt.maxTotalSize
+			w.MaxAge = tt.maxAge
+
+			for i := 0; i < 4; i++ {
+				clock.advance(time.Hour)
+				if err := w.Write(newTestMessage("foo")); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Rotate(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			got := readDir(t, dir)
+			if strings.Join(tt.want, " ") != strings.Join(got, " ") {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+			for _, name := range got {
+				if name == "app.log" {
+					continue
+				}
+				if want, got := "<165>1 - - - - - - foo\n", readFile(t, filepath.Join(dir, name)); want != got {
+					t.Fatalf("want=%q, got=%q.", want, got)
+				}
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "keep all",
+			want: []string{
+				"app-2023-02-16T01-00-00.000.log",
+				"app-2023-02-16T02-00-00.000.log",
+				"app-2023-02-16T03-00-00.000.log",
+				"app-2023-02-16T04-00-00.000.log",
+				"app.log",
+			},
+		},
+		{
+			name:       "count with compression",
+			compress:   true,
+			maxBackups: 2,
+			want: []string{
+				"app-2023-02-16T03-00-00.000.log.gz",
+				"app-2023-02-16T04-00-00.000.log.gz",
+				"app.log",
+			},
+		},
+		{
+			name:         "total size",
+			maxTotalSize: 3 * int64(len("<165>1 - - - - - - foo\n")),
+			want: []string{
+				"app-2023-02-16T02-00-00.000.log",
+				"app-2023-02-16T03-00-00.000.log",
+				"app-2023-02-16T04-00-00.000.log",
+				"app.log",
+			},
+		},
+		{
+			name:   "age",
+			maxAge: 90 * time.Minute,
+			want: []string{
+				"app-2023-02-16T03-00-00.000.log",
+				"app-2023-02-16T04-00-00.000.log",
+				"app.log",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestRotatingFileWriter_Rotate_sameTime(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2023, 02, 16, 0, 0, 0, 0, time.UTC)}
+	w, dir := newTestRotatingFileWriter(t, clock)
+	w.Compress = true
+	w.MaxBackups = 2
+
+	for _, msg := range []string{"first", "second", "third"} {
+		if err := w.Write(newTestMessage(msg)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, name := range readDir(t, dir) {
+		got = append(got, name+": "+readFile(t, filepath.Join(dir, name)))
+	}
+	want := []string{
+		"app-2023-02-16T00-00-00.000-1.log.gz: <165>1 - - - - - - second\n",
+		"app-2023-02-16T00-00-00.000-2.log.gz: <165>1 - - - - - - third\n",
+		"app.log: ",
+	}
+	if strings.Join(want, "") != strings.Join(got, "") {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+}
+
+func TestRotatingFileWriter_Write_renameError(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2023, 02, 16, 0, 0, 0, 0, time.UTC)}
+	w, dir := newTestRotatingFileWriter(t, clock)
+	w.MaxSize = 30
+
+	errRename := errors.New("rename error")
+	w.rename = func(oldpath, newpath string) error { return errRename }
+	var handled []error
+	w.ErrorHandler = func(err error) { handled = append(handled, err) }
+
+	for _, msg := range []string{"foo", "bar", "baz"} {
+		if err := w.Write(newTestMessage(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(handled) != 2 || !errors.Is(handled[0], errRename) {
+		t.Fatalf("want=%v, got=%v.", []error{errRename, errRename}, handled)
+	}
+
+	// rotation resumes once renaming works again.
+	w.rename = os.Rename
+	if err := w.Write(newTestMessage("qux")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, name := range readDir(t, dir) {
+		got = append(got, name+": "+readFile(t, filepath.Join(dir, name)))
+	}
+	want := []string{
+		"app-2023-02-16T00-00-00.000.log: <165>1 - - - - - - foo\n<165>1 - - - - - - bar\n<165>1 - - - - - - baz\n",
+		"app.log: <165>1 - - - - - - qux\n",
+	}
+	if strings.Join(want, "") != strings.Join(got, "") {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+}
+
+func TestNewRotatingFileWriter_append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewRotatingFileWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(newTestMessage("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(newTestMessage("bar")); err != ErrWriterClosed {
+		t.Fatalf("want-error=%v, error=%v.", ErrWriterClosed, err)
+	}
+
+	if want, got := "existing\n<165>1 - - - - - - foo\n", readFile(t, path); want != got {
+		t.Fatalf("want=%q, got=%q.", want, got)
+	}
+}
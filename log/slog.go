@@ -0,0 +1,154 @@
+package log
+
+import (
+	"context"
+	"github.com/a-skua/busybox-go/option"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SlogHandler is a slog.Handler writing through a Log. Attributes become params of the
+// SD-ID given to NewSlogHandler, which should have the name@<private enterprise number>
+// form of RFC 5424 section 7.2.2. Group names and the key are joined with "." to form
+// the param name, and characters not allowed in an SD-NAME are replaced with '_'.
+type SlogHandler struct {
+	log    *Log
+	id     MetadataID
+	meta   []Metadata
+	groups []string
+}
+
+func NewSlogHandler(log *Log, id MetadataID) *SlogHandler {
+	return &SlogHandler{
+		log: log,
+		id:  id,
+	}
+}
+
+func slogSeverity(level slog.Level) Severity {
+	switch {
+	case level < slog.LevelInfo:
+		return SeverityDebug
+	case level < slog.LevelInfo+2:
+		return SeverityInformational
+	case level < slog.LevelWarn:
+		return SeverityNotice
+	case level < slog.LevelError:
+		return SeverityWarning
+	case level < slog.LevelError+4:
+		return SeverityError
+	case level < slog.LevelError+8:
+		return SeverityCritical
+	case level < slog.LevelError+12:
+		return SeverityAlert
+	default:
+		return SeverityEmergency
+	}
+}
+
+func slogValue(v slog.Value) string {
+	if v.Kind() == slog.KindTime {
+		return v.Time().Format(time.RFC3339Nano)
+	}
+	return v.String()
+}
+
+func (h *SlogHandler) appendAttr(meta []Metadata, groups []string, attr slog.Attr) []Metadata {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return meta
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, a := range attr.Value.Group() {
+			meta = h.appendAttr(meta, groups, a)
+		}
+		return meta
+	}
+
+	name := strings.Join(append(groups[:len(groups):len(groups)], attr.Key), ".")
+	if name == "" {
+		return meta
+	}
+	param := NewMetadataParam(
+		MetadataName(sanitizeName(name, MaxSDNameSize, isSDNameChar)),
+		MetadataValue(strings.ToValidUTF8(slogValue(attr.Value), "�")),
+	)
+
+	for i := range meta {
+		if meta[i].ID == h.id {
+			meta[i].Params = append(meta[i].Params, param)
+			return meta
+		}
+	}
+	return append(meta, NewMetadata(h.id, param))
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.log.Enabled(slogSeverity(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var attrs []Metadata
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = h.appendAttr(attrs, h.groups, attr)
+		return true
+	})
+
+	metadata := mergeMetadata(h.log.Metadata, MetadataFromContext(ctx))
+	metadata = mergeMetadata(metadata, h.meta)
+	metadata = mergeMetadata(metadata, attrs)
+
+	timestamp := option.None[Timestamp]()
+	if !r.Time.IsZero() {
+		timestamp = option.Some(Timestamp(r.Time))
+	}
+
+	var msg []any
+	if r.Message != "" {
+		msg = []any{r.Message}
+	}
+
+	return h.log.Writer.Write(NewMessage(
+		NewHeader(
+			NewPriority(h.log.Facility, slogSeverity(r.Level)),
+			h.log.Version,
+			timestamp,
+			h.log.HostName,
+			h.log.AppName,
+			h.log.Proccess,
+			h.log.MessageID,
+		),
+		metadata,
+		msg...,
+	))
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	var meta []Metadata
+	for _, attr := range attrs {
+		meta = h.appendAttr(meta, h.groups, attr)
+	}
+
+	child := *h
+	child.meta = mergeMetadata(h.meta, meta)
+	return &child
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	child := *h
+	child.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &child
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"context"
+	"github.com/a-skua/busybox-go/option"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+)
+
+func TestSlogSeverity(t *testing.T) {
+	type test struct {
+		name  string
+		level slog.Level
+		want  Severity
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slogSeverity(tt.level)
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:  "debug",
+			level: slog.LevelDebug,
+			want:  SeverityDebug,
+		},
+		{
+			name:  "info",
+			level: slog.LevelInfo,
+			want:  SeverityInformational,
+		},
+		{
+			name:  "info+2",
+			level: slog.LevelInfo + 2,
+			want:  SeverityNotice,
+		},
+		{
+			name:  "warn",
+			level: slog.LevelWarn,
+			want:  SeverityWarning,
+		},
+		{
+			name:  "error",
+			level: slog.LevelError,
+			want:  SeverityError,
+		},
+		{
+			name:  "error+4",
+			level: slog.LevelError + 4,
+			want:  SeverityCritical,
+		},
+		{
+			name:  "error+8",
+			level: slog.LevelError + 8,
+			want:  SeverityAlert,
+		},
+		{
+			name:  "error+12",
+			level: slog.LevelError + 12,
+			want:  SeverityEmergency,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	w := &messageWriter{}
+	log := &Log{
+		Facility: FacilityUserLevelMessages,
+		Version:  1,
+		Metadata: []Metadata{NewMetadata("app@1", NewMetadataParam("version", "1.0"))},
+		Writer:   w,
+		Level:    SeverityInformational,
+	}
+	logger := slog.New(NewSlogHandler(log, "slog@32473"))
+
+	ctx := ContextWithMetadata(context.Background(), NewMetadata("req@1", NewMetadataParam("id", "abc")))
+	logger.InfoContext(ctx, "hello", "user", "alice", slog.Group("http", "method", "GET", slog.Group("url", "path", "/")))
+	logger.Debug("filtered")
+	logger.With("component", "db").WithGroup("query").Warn("slow", "ms", 1200)
+	logger.Info("invalid names", "a b", 1, `x="y"]`, 2)
+
+	want := []string{
+		`<14>1 - - - - - [app@1 version="1.0"][req@1 id="abc"][slog@32473 user="alice" http.method="GET" http.url.path="/"] hello`,
+		`<12>1 - - - - - [app@1 version="1.0"][slog@32473 component="db" query.ms="1200"] slow`,
+		`<14>1 - - - - - [app@1 version="1.0"][slog@32473 a_b="1" x__y__="2"] invalid names`,
+	}
+	if len(want) != len(w.messages) {
+		t.Fatalf("want=%v, got=%v.", len(want), len(w.messages))
+	}
+	for i, msg := range w.messages {
+		msg.Header.Timestamp = option.None[Timestamp]()
+		if err := msg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if want[i] != msg.String() {
+			t.Fatalf("want=%v, got=%v.", want[i], msg.String())
+		}
+	}
+}
+
+func TestSlogHandler_slogtest(t *testing.T) {
+	const id = "slog@32473"
+	w := &messageWriter{}
+	h := NewSlogHandler(&Log{Writer: w}, id)
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, msg := range w.messages {
+			m := map[string]any{
+				slog.LevelKey:   msg.Header.Priority.Severity,
+				slog.MessageKey: msg.text(),
+			}
+			if msg.Header.Timestamp.Valid {
+				m[slog.TimeKey] = time.Time(msg.Header.Timestamp.Value)
+			}
+			for _, meta := range msg.Metadata {
+				group := m
+				if meta.ID != id {
+					group = map[string]any{}
+					m[meta.ID.String()] = group
+				}
+				for _, param := range meta.Params {
+					keys := strings.Split(param.Name.String(), ".")
+					g := group
+					for _, key := range keys[:len(keys)-1] {
+						if _, ok := g[key]; !ok {
+							g[key] = map[string]any{}
+						}
+						g = g[key].(map[string]any)
+					}
+					g[keys[len(keys)-1]] = param.Value.String()
+				}
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	stdlog "log"
+	"strings"
+)
+
+// StdlibWriter is an io.Writer turning each line written by the standard
+// library log package into a message of Logger. The date and time written by the
+// Ldate, Ltime and Lmicroseconds flags are stripped since the message has its own
+// TIMESTAMP, so stdlog.SetOutput works without changing the flags.
+type StdlibWriter struct {
+	Logger   Logger
+	Severity Severity
+	// DetectSeverity uses a leading "ERROR:" or "[warn]" style prefix, if any,
+	// as the Severity of the line and strips it.
+	DetectSeverity bool
+}
+
+func NewStdlibWriter(logger Logger, severity Severity) *StdlibWriter {
+	return &StdlibWriter{
+		Logger:   logger,
+		Severity: severity,
+	}
+}
+
+// NewStdlibLogger returns a standard library logger, e.g. for http.Server.ErrorLog.
+func NewStdlibLogger(logger Logger, severity Severity) *stdlog.Logger {
+	return stdlog.New(NewStdlibWriter(logger, severity), "", 0)
+}
+
+// matchDigits reports whether s starts with layout, where '0' matches any digit.
+func matchDigits(s, layout string) bool {
+	if len(s) < len(layout) {
+		return false
+	}
+	for i := 0; i < len(layout); i++ {
+		if layout[i] == '0' {
+			if s[i] < '0' || s[i] > '9' {
+				return false
+			}
+		} else if s[i] != layout[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// stripStdlibTime removes a leading "2009/01/23 01:23:23.123123 " as written by the standard library.
+func stripStdlibTime(line string) string {
+	if matchDigits(line, "0000/00/00 ") {
+		line = line[len("0000/00/00 "):]
+	}
+	if matchDigits(line, "00:00:00.000000 ") {
+		return line[len("00:00:00.000000 "):]
+	}
+	if matchDigits(line, "00:00:00 ") {
+		return line[len("00:00:00 "):]
+	}
+	return line
+}
+
+// detectSeverity parses prefixes such as "ERROR:", "[WARN]" and "crit: ".
+func detectSeverity(line string) (Severity, string, bool) {
+	word, rest := "", ""
+	if strings.HasPrefix(line, "[") {
+		end := strings.IndexByte(line, ']')
+		if end < 0 {
+			return 0, line, false
+		}
+		word, rest = line[1:end], line[end+1:]
+		rest = strings.TrimPrefix(rest, ":")
+	} else {
+		end := strings.IndexByte(line, ':')
+		if end < 0 {
+			return 0, line, false
+		}
+		word, rest = line[:end], line[end+1:]
+	}
+
+	for _, r := range word {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return 0, line, false
+		}
+	}
+	severity, err := ParseSeverity(word)
+	if err != nil {
+		return 0, line, false
+	}
+	return severity, strings.TrimLeft(rest, " \t"), true
+}
+
+func (w *StdlibWriter) Write(p []byte) (int, error) {
+	var errs []error
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		text := stripStdlibTime(strings.TrimSuffix(string(line), "\r"))
+		if text == "" {
+			continue
+		}
+
+		severity := w.Severity
+		if w.DetectSeverity {
+			if s, rest, ok := detectSeverity(text); ok {
+				severity, text = s, rest
+			}
+		}
+		if err := logAt(w.Logger, severity, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
@@ -0,0 +1,162 @@
+package log
+
+import (
+	"github.com/a-skua/busybox-go/option"
+	stdlog "log"
+	"testing"
+)
+
+func TestDetectSeverity(t *testing.T) {
+	type test struct {
+		name     string
+		line     string
+		severity Severity
+		rest     string
+		ok       bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, rest, ok := detectSeverity(tt.line)
+			if tt.ok != ok {
+				t.Fatalf("want=%v, got=%v.", tt.ok, ok)
+			}
+			if tt.severity != severity {
+				t.Fatalf("want=%v, got=%v.", tt.severity, severity)
+			}
+			if tt.rest != rest {
+				t.Fatalf("want=%v, got=%v.", tt.rest, rest)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "colon",
+			line:     "ERROR: disk full",
+			severity: SeverityError,
+			rest:     "disk full",
+			ok:       true,
+		},
+		{
+			name:     "brackets",
+			line:     "[warn] slow query",
+			severity: SeverityWarning,
+			rest:     "slow query",
+			ok:       true,
+		},
+		{
+			name:     "alias",
+			line:     "crit: out of memory",
+			severity: SeverityCritical,
+			rest:     "out of memory",
+			ok:       true,
+		},
+		{
+			name: "unknown prefix",
+			line: "http: TLS handshake error",
+			rest: "http: TLS handshake error",
+		},
+		{
+			name: "number",
+			line: "3: not a severity",
+			rest: "3: not a severity",
+		},
+		{
+			name: "no prefix",
+			line: "hello, syslog!",
+			rest: "hello, syslog!",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestStdlibWriter(t *testing.T) {
+	w := &messageWriter{}
+	log := &Log{
+		Facility: FacilityUserLevelMessages,
+		Version:  1,
+		Writer:   w,
+		Level:    SeverityInformational,
+	}
+	bridge := NewStdlibWriter(log, SeverityNotice)
+	bridge.DetectSeverity = true
+	std := stdlog.New(bridge, "", 0)
+
+	std.Print("http: TLS handshake error")
+	std.Print("ERROR: disk full\nsecond line\r\n")
+	std.Print("DEBUG: filtered")
+
+	want := []string{
+		"<13>1 - - - - - - http: TLS handshake error",
+		"<11>1 - - - - - - disk full",
+		"<13>1 - - - - - - second line",
+	}
+	if len(want) != len(w.messages) {
+		t.Fatalf("want=%v, got=%v.", len(want), len(w.messages))
+	}
+	for i, msg := range w.messages {
+		msg.Header.Timestamp = option.None[Timestamp]()
+		if want[i] != msg.String() {
+			t.Fatalf("want=%v, got=%v.", want[i], msg.String())
+		}
+	}
+}
+
+func TestNewStdlibLogger(t *testing.T) {
+	w := &messageWriter{}
+	std := NewStdlibLogger(&Log{Version: 1, Writer: w}, SeverityError)
+	std.Printf("accept error: %v", "too many open files")
+
+	if len(w.messages) != 1 {
+		t.Fatalf("want=%v, got=%v.", 1, len(w.messages))
+	}
+	msg := w.messages[0]
+	msg.Header.Timestamp = option.None[Timestamp]()
+	if want := "<3>1 - - - - - - accept error: too many open files"; want != msg.String() {
+		t.Fatalf("want=%v, got=%v.", want, msg.String())
+	}
+}
+
+func TestStdlibWriter_SetOutput(t *testing.T) {
+	w := &messageWriter{}
+	bridge := NewStdlibWriter(&Log{Version: 1, Writer: w}, SeverityNotice)
+	bridge.DetectSeverity = true
+
+	defer stdlog.SetOutput(stdlog.Writer())
+	defer stdlog.SetFlags(stdlog.Flags())
+	stdlog.SetOutput(bridge)
+
+	for _, flags := range []int{stdlog.LstdFlags, stdlog.LstdFlags | stdlog.Lmicroseconds | stdlog.LUTC, stdlog.Ltime} {
+		stdlog.SetFlags(flags)
+		stdlog.Print("ERROR: disk full")
+	}
+
+	if len(w.messages) != 3 {
+		t.Fatalf("want=%v, got=%v.", 3, len(w.messages))
+	}
+	for _, msg := range w.messages {
+		msg.Header.Timestamp = option.None[Timestamp]()
+		if want := "<3>1 - - - - - - disk full"; want != msg.String() {
+			t.Fatalf("want=%v, got=%v.", want, msg.String())
+		}
+	}
+}
+
+func TestStripStdlibTime(t *testing.T) {
+	for line, want := range map[string]string{
+		"2009/01/23 01:23:23 foo":        "foo",
+		"2009/01/23 01:23:23.123123 foo": "foo",
+		"01:23:23 foo":                   "foo",
+		"2009/01/23 foo":                 "foo",
+		"12:00 lunch":                    "12:00 lunch",
+		"foo":                            "foo",
+	} {
+		if got := stripStdlibTime(line); want != got {
+			t.Fatalf("want=%v, got=%v.", want, got)
+		}
+	}
+}
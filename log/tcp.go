@@ -0,0 +1,150 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RFC6587 Transmission of Syslog Messages over TCP: https://www.rfc-editor.org/rfc/rfc6587
+type Framing uint8
+
+const (
+	FramingOctetCounting Framing = iota
+	FramingNonTransparent
+)
+
+// frame may modify data. Non-transparent framing ends a message at LF, so trailing LFs
+// are removed and embedded ones, e.g. of a stack trace, are replaced with spaces.
+func (f Framing) frame(data []byte) []byte {
+	switch f {
+	case FramingNonTransparent:
+		data = bytes.TrimRight(data, "\n")
+		for i, c := range data {
+			if c == '\n' {
+				data[i] = ' '
+			}
+		}
+		return append(data, '\n')
+	default:
+		return append(append([]byte(strconv.Itoa(len(data))), ' '), data...)
+	}
+}
+
+// Defaults of the timeouts of stream Writers, which hold their lock while connecting and writing.
+const (
+	DefaultDialTimeout  = 5 * time.Second
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+type TCPWriter struct {
+	Formatter Formatter
+	// DialTimeout bounds each reconnection and WriteTimeout each write, so that a relay
+	// that stops reading makes Write fail instead of blocking. Zero means no timeout.
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+	framing      Framing
+	dial         func(dialer *net.Dialer) (net.Conn, error)
+	mu           sync.Mutex
+	conn         net.Conn
+	closed       bool
+}
+
+func NewTCPWriter(addr string, framing Framing) (*TCPWriter, error) {
+	return newStreamWriter(framing, func(dialer *net.Dialer) (net.Conn, error) {
+		return dialer.Dial("tcp", addr)
+	})
+}
+
+func newStreamWriter(framing Framing, dial func(dialer *net.Dialer) (net.Conn, error)) (*TCPWriter, error) {
+	w := &TCPWriter{
+		DialTimeout:  DefaultDialTimeout,
+		WriteTimeout: DefaultWriteTimeout,
+		framing:      framing,
+		dial:         dial,
+	}
+
+	if err := w.reconnect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *TCPWriter) reconnect() error {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	conn, err := w.dial(&net.Dialer{Timeout: w.DialTimeout})
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// writeConn writes data to conn within timeout, if any.
+func writeConn(conn net.Conn, data []byte, timeout time.Duration) error {
+	if timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+	}
+	_, err := conn.Write(data)
+	return err
+}
+
+func (w *TCPWriter) Write(msg *Message) error {
+	data, err := format(w.Formatter, msg)
+	if err != nil {
+		return err
+	}
+	data = w.framing.frame(data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return net.ErrClosed
+	}
+
+	if w.conn == nil || !alive(w.conn) {
+		if err := w.reconnect(); err != nil {
+			return err
+		}
+	}
+
+	err = writeConn(w.conn, data, w.WriteTimeout)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		// the relay is not reading; a part of the frame may have been sent.
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+
+	if err := w.reconnect(); err != nil {
+		return err
+	}
+	return writeConn(w.conn, data, w.WriteTimeout)
+}
+
+func (w *TCPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,235 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readOctetCountingFrame(r *bufio.Reader) (string, error) {
+	size, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func readNonTransparentFrame(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	return strings.TrimSuffix(line, "\n"), err
+}
+
+func TestFraming_frame(t *testing.T) {
+	type test struct {
+		name    string
+		framing Framing
+		data    string
+		want    string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(tt.framing.frame([]byte(tt.data)))
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:    "octet counting",
+			framing: FramingOctetCounting,
+			data:    "<165>1 - - - - - -",
+			want:    "18 <165>1 - - - - - -",
+		},
+		{
+			name:    "non-transparent",
+			framing: FramingNonTransparent,
+			data:    "<165>1 - - - - - -",
+			want:    "<165>1 - - - - - -\n",
+		},
+		{
+			name:    "non-transparent with embedded LF",
+			framing: FramingNonTransparent,
+			data:    "<165>1 - - - - - - panic\ngoroutine 1\n",
+			want:    "<165>1 - - - - - - panic goroutine 1\n",
+		},
+		{
+			name:    "octet counting keeps LF",
+			framing: FramingOctetCounting,
+			data:    "<165>1 - - - - - - a\nb",
+			want:    "22 <165>1 - - - - - - a\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestTCPWriter_Write(t *testing.T) {
+	type test struct {
+		name    string
+		framing Framing
+		read    func(*bufio.Reader) (string, error)
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			conns := make(chan net.Conn)
+			go func() {
+				for {
+					conn, err := ln.Accept()
+					if err != nil {
+						close(conns)
+						return
+					}
+					conns <- conn
+				}
+			}()
+
+			w, err := NewTCPWriter(ln.Addr().String(), tt.framing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			conn := <-conns
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			r := bufio.NewReader(conn)
+			for _, want := range []string{"foo", "bar"} {
+				if err := w.Write(newTestMessage(want)); err != nil {
+					t.Fatal(err)
+				}
+				got, err := tt.read(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := "<165>1 - - - - - - " + want; want != got {
+					t.Fatalf("want=%v, got=%v.", want, got)
+				}
+			}
+
+			// the relay drops the connection; the next write reconnects.
+			conn.Close()
+			time.Sleep(10 * time.Millisecond)
+			if err := w.Write(newTestMessage("baz")); err != nil {
+				t.Fatal(err)
+			}
+			conn = <-conns
+			defer conn.Close()
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			got, err := tt.read(bufio.NewReader(conn))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "<165>1 - - - - - - baz"; want != got {
+				t.Fatalf("want=%v, got=%v.", want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:    "octet counting",
+			framing: FramingOctetCounting,
+			read:    readOctetCountingFrame,
+		},
+		{
+			name:    "non-transparent",
+			framing: FramingNonTransparent,
+			read:    readNonTransparentFrame,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestTCPWriter_Close(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	w, err := NewTCPWriter(ln.Addr().String(), FramingOctetCounting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(newTestMessage("foo")); err == nil {
+		t.Fatalf("want-error=%v, error=%v.", true, err)
+	}
+}
+
+func TestTCPWriter_WriteTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the relay accepts but never reads.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-stop
+	}()
+
+	w, err := NewTCPWriter(ln.Addr().String(), FramingOctetCounting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.WriteTimeout = 50 * time.Millisecond
+
+	msg := newTestMessage(strings.Repeat("a", 1<<20))
+	done := make(chan error)
+	go func() {
+		for {
+			if err := w.Write(msg); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("want-error=%v, error=%v.", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("want Write to time out")
+	}
+}
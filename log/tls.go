@@ -0,0 +1,143 @@
+package log
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+)
+
+// RFC5425 Transport Layer Security (TLS) Transport Mapping for Syslog: https://www.rfc-editor.org/rfc/rfc5425
+var ErrFingerprintMismatch = errors.New("log: certificate fingerprint mismatch")
+
+var fingerprintHashes = []struct {
+	name string
+	hash crypto.Hash
+}{
+	{"MD5", crypto.MD5},
+	{"SHA-1", crypto.SHA1},
+	{"SHA-224", crypto.SHA224},
+	{"SHA-256", crypto.SHA256},
+	{"SHA-384", crypto.SHA384},
+	{"SHA-512", crypto.SHA512},
+}
+
+type Fingerprint struct {
+	Hash crypto.Hash
+	Sum  []byte
+}
+
+func NewFingerprint(hash crypto.Hash, cert *x509.Certificate) Fingerprint {
+	h := hash.New()
+	h.Write(cert.Raw)
+	return Fingerprint{
+		Hash: hash,
+		Sum:  h.Sum(nil),
+	}
+}
+
+// ParseFingerprint parses the RFC 5425 textual form, e.g. "SHA-256:E3:B0:...".
+func ParseFingerprint(s string) (Fingerprint, error) {
+	name, sum, ok := strings.Cut(s, ":")
+	if !ok {
+		return Fingerprint{}, errors.New("log: invalid fingerprint: " + s)
+	}
+
+	for _, h := range fingerprintHashes {
+		if !strings.EqualFold(h.name, name) {
+			continue
+		}
+		b, err := hex.DecodeString(strings.ReplaceAll(sum, ":", ""))
+		if err != nil {
+			return Fingerprint{}, errors.New("log: invalid fingerprint: " + s)
+		}
+		if len(b) != h.hash.Size() {
+			return Fingerprint{}, errors.New("log: invalid fingerprint length: " + s)
+		}
+		return Fingerprint{
+			Hash: h.hash,
+			Sum:  b,
+		}, nil
+	}
+
+	return Fingerprint{}, errors.New("log: unsupported fingerprint hash: " + name)
+}
+
+func (f Fingerprint) String() string {
+	name := f.Hash.String()
+	for _, h := range fingerprintHashes {
+		if h.hash == f.Hash {
+			name = h.name
+		}
+	}
+
+	sum := make([]string, len(f.Sum))
+	for i, b := range f.Sum {
+		sum[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return name + ":" + strings.Join(sum, ":")
+}
+
+func (f Fingerprint) Match(cert *x509.Certificate) bool {
+	if !f.Hash.Available() {
+		return false
+	}
+	return bytes.Equal(f.Sum, NewFingerprint(f.Hash, cert).Sum)
+}
+
+// NewTLSWriter connects to addr with octet-counting framing over TLS.
+// When fingerprints are given, the server certificate must match one of them;
+// if config has no RootCAs, the fingerprints replace certification path validation.
+func NewTLSWriter(addr string, config *tls.Config, fingerprints ...Fingerprint) (*TCPWriter, error) {
+	if config == nil {
+		config = &tls.Config{}
+	}
+	config = config.Clone()
+
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS12
+	}
+	if config.ServerName == "" && !config.InsecureSkipVerify {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	if len(fingerprints) > 0 {
+		if config.RootCAs == nil {
+			config.InsecureSkipVerify = true
+		}
+		verify := config.VerifyConnection
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			if verify != nil {
+				if err := verify(state); err != nil {
+					return err
+				}
+			}
+			if len(state.PeerCertificates) == 0 {
+				return ErrFingerprintMismatch
+			}
+			for _, f := range fingerprints {
+				if f.Match(state.PeerCertificates[0]) {
+					return nil
+				}
+			}
+			return ErrFingerprintMismatch
+		}
+	}
+
+	// the dialer's timeout bounds the handshake as well.
+	return newStreamWriter(FramingOctetCounting, func(dialer *net.Dialer) (net.Conn, error) {
+		return tls.DialWithDialer(dialer, "tcp", addr, config)
+	})
+}
@@ -0,0 +1,255 @@
+package log
+
+import (
+	"bufio"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, name string) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}, cert
+}
+
+func TestNewTLSWriter(t *testing.T) {
+	serverCert, serverX509 := newTestCertificate(t, "server")
+	clientCert, clientX509 := newTestCertificate(t, "client")
+	_, otherX509 := newTestCertificate(t, "other")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverX509)
+	clients := x509.NewCertPool()
+	clients.AddCert(clientX509)
+
+	type test struct {
+		name         string
+		config       *tls.Config
+		fingerprints []Fingerprint
+		wantErr      bool
+		errIs        error
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    clients,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			received := make(chan string, 1)
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				conn.SetReadDeadline(time.Now().Add(time.Second))
+				msg, _ := readOctetCountingFrame(bufio.NewReader(conn))
+				received <- msg
+			}()
+
+			w, err := NewTLSWriter(ln.Addr().String(), tt.config, tt.fingerprints...)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Fatalf("want=%v, got=%v.", tt.errIs, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			defer w.Close()
+
+			if err := w.Write(newTestMessage("secret")); err != nil {
+				t.Fatal(err)
+			}
+			if want, got := "<165>1 - - - - - - secret", <-received; want != got {
+				t.Fatalf("want=%v, got=%v.", want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "root CA",
+			config: &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+				RootCAs:      roots,
+			},
+		},
+		{
+			name: "fingerprint without root CA",
+			config: &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+			},
+			fingerprints: []Fingerprint{NewFingerprint(crypto.SHA256, serverX509)},
+		},
+		{
+			name: "fingerprint mismatch",
+			config: &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+				RootCAs:      roots,
+			},
+			fingerprints: []Fingerprint{NewFingerprint(crypto.SHA256, otherX509)},
+			wantErr:      true,
+			errIs:        ErrFingerprintMismatch,
+		},
+		{
+			name: "server name mismatch",
+			config: &tls.Config{
+				Certificates: []tls.Certificate{clientCert},
+				RootCAs:      roots,
+				ServerName:   "example.com",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestParseFingerprint(t *testing.T) {
+	type test struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFingerprint(tt.value)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.want != got.String() {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:  "sha-1",
+			value: "sha-1:e1:fa:d3:38:2c:e6:c0:a8:4c:0d:c5:89:0e:fa:88:8f:2b:a2:4c:e7",
+			want:  "SHA-1:E1:FA:D3:38:2C:E6:C0:A8:4C:0D:C5:89:0E:FA:88:8F:2B:A2:4C:E7",
+		},
+		{
+			name:    "unknown hash",
+			value:   "CRC32:00:00:00:00",
+			wantErr: true,
+		},
+		{
+			name:    "invalid length",
+			value:   "SHA-256:00:01",
+			wantErr: true,
+		},
+		{
+			name:    "missing hash",
+			value:   "0001",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestTLSWriter_handshakeTimeout(t *testing.T) {
+	serverCert, serverX509 := newTestCertificate(t, "server")
+	roots := x509.NewCertPool()
+	roots.AddCert(serverX509)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the first connection completes the handshake; the relay then hangs on reconnects.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn, first bool) {
+				defer conn.Close()
+				if first {
+					tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{serverCert}}).Handshake()
+				}
+				<-stop
+			}(conn, i == 0)
+		}
+	}()
+
+	w, err := NewTLSWriter(ln.Addr().String(), &tls.Config{RootCAs: roots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.DialTimeout = 50 * time.Millisecond
+
+	// the relay drops the connection.
+	w.conn.Close()
+	w.conn = nil
+
+	start := time.Now()
+	if err := w.Write(newTestMessage("foo")); err == nil {
+		t.Fatalf("want-error=%v, error=%v.", true, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("want=%v, got=%v.", "handshake to time out", elapsed)
+	}
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"unicode/utf8"
+)
+
+// RFC5426 Transmission of Syslog Messages over UDP: https://www.rfc-editor.org/rfc/rfc5426
+const (
+	UDPMinMessageSize         = 480
+	UDPRecommendedMessageSize = 2048
+)
+
+var ErrMessageTooLarge = errors.New("log: message too large")
+
+type UDPWriter struct {
+	// MaxSize limits the size of a datagram in octets. Zero means no limit.
+	MaxSize int
+	// Truncate cuts messages longer than MaxSize instead of rejecting them.
+	Truncate  bool
+	Formatter Formatter
+	conn      *net.UDPConn
+}
+
+func NewUDPWriter(addr string) (*UDPWriter, error) {
+	return NewUDPWriterFrom("", addr)
+}
+
+// NewUDPWriterFrom binds the local side of the socket to laddr.
+func NewUDPWriterFrom(laddr, addr string) (*UDPWriter, error) {
+	var local *net.UDPAddr
+	if laddr != "" {
+		var err error
+		local, err = net.ResolveUDPAddr("udp", laddr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remote, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialUDP("udp", local, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UDPWriter{
+		MaxSize:  UDPRecommendedMessageSize,
+		Truncate: true,
+		conn:     conn,
+	}, nil
+}
+
+func (w *UDPWriter) Write(msg *Message) error {
+	data, err := format(w.Formatter, msg)
+	if err != nil {
+		return err
+	}
+	if w.MaxSize > 0 && len(data) > w.MaxSize {
+		if !w.Truncate {
+			return fmt.Errorf("%w: %d octets exceeds %d", ErrMessageTooLarge, len(data), w.MaxSize)
+		}
+		data = truncate(data, w.MaxSize)
+	}
+
+	_, err = w.conn.Write(data)
+	return err
+}
+
+func (w *UDPWriter) LocalAddr() net.Addr {
+	return w.conn.LocalAddr()
+}
+
+func (w *UDPWriter) Close() error {
+	return w.conn.Close()
+}
+
+// truncate cuts data to at most size octets without splitting a UTF-8 sequence.
+func truncate(data []byte, size int) []byte {
+	if len(data) <= size {
+		return data
+	}
+	n := size
+	for n > 0 && n > size-utf8.UTFMax && !utf8.RuneStart(data[n]) {
+		n--
+	}
+	if !utf8.RuneStart(data[n]) {
+		n = size
+	}
+	return data[:n]
+}
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestMessage(msg ...any) *Message {
+	return NewMessage(
+		NewHeader(
+			NewPriority(FacilityLocalUse4, SeverityNotice),
+			1,
+			option.None[Timestamp](),
+			option.None[HostName](),
+			option.None[AppName](),
+			option.None[ProcessID](),
+			option.None[MessageID](),
+		),
+		[]Metadata{},
+		msg...,
+	)
+}
+
+func TestUDPWriter_Write(t *testing.T) {
+	type test struct {
+		name     string
+		maxSize  int
+		truncate bool
+		message  *Message
+		want     string
+		wantErr  error
+	}
+
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewUDPWriterFrom("127.0.0.1:0", server.LocalAddr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			w.MaxSize = tt.maxSize
+			w.Truncate = tt.truncate
+
+			err = w.Write(tt.message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			buf := make([]byte, 4096)
+			server.SetReadDeadline(time.Now().Add(time.Second))
+			n, addr, err := server.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr.String() != w.LocalAddr().String() {
+				t.Fatalf("want=%v, got=%v.", w.LocalAddr(), addr)
+			}
+			if got := string(buf[:n]); tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "one message per datagram",
+			maxSize:  UDPRecommendedMessageSize,
+			truncate: true,
+			message:  newTestMessage("hello, syslog!"),
+			want:     "<165>1 - - - - - - hello, syslog!",
+		},
+		{
+			name:     "truncate",
+			maxSize:  UDPMinMessageSize,
+			truncate: true,
+			message:  newTestMessage(strings.Repeat("a", 1000)),
+			want:     "<165>1 - - - - - - " + strings.Repeat("a", UDPMinMessageSize-len("<165>1 - - - - - - ")),
+		},
+		{
+			name:     "too large",
+			maxSize:  UDPMinMessageSize,
+			truncate: false,
+			message:  newTestMessage(strings.Repeat("a", 1000)),
+			wantErr:  ErrMessageTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	type test struct {
+		name string
+		data string
+		size int
+		want string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(truncate([]byte(tt.data), tt.size))
+			if tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "short",
+			data: "abc",
+			size: 5,
+			want: "abc",
+		},
+		{
+			name: "ascii",
+			data: "abcdef",
+			size: 3,
+			want: "abc",
+		},
+		{
+			name: "keep utf-8 sequence",
+			data: "aあい",
+			size: 5,
+			want: "aあ",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
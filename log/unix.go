@@ -0,0 +1,125 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// UnixWriter sends Messages to the local syslog daemon. Datagram sockets carry
+// one Message per datagram, stream sockets use non-transparent framing.
+type UnixWriter struct {
+	Formatter Formatter
+	// WriteTimeout bounds each write so that a wedged daemon makes Write fail
+	// instead of blocking. Zero means no timeout.
+	WriteTimeout time.Duration
+	paths        []string
+	mu           sync.Mutex
+	conn         net.Conn
+	stream       bool
+	closed       bool
+}
+
+// NewLocalWriter connects to the first available of /dev/log, /var/run/syslog and /var/run/log.
+func NewLocalWriter() (*UnixWriter, error) {
+	return newUnixWriter(localSyslogPaths)
+}
+
+func NewUnixWriter(path string) (*UnixWriter, error) {
+	return newUnixWriter([]string{path})
+}
+
+func newUnixWriter(paths []string) (*UnixWriter, error) {
+	w := &UnixWriter{
+		WriteTimeout: DefaultWriteTimeout,
+		paths:        paths,
+	}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *UnixWriter) connect() error {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+
+	var errs []error
+	for _, path := range w.paths {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			w.conn = conn
+			w.stream = network == "unix"
+			return nil
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *UnixWriter) write(data []byte) error {
+	if w.stream {
+		data = FramingNonTransparent.frame(data)
+	}
+	return writeConn(w.conn, data, w.WriteTimeout)
+}
+
+func (w *UnixWriter) Write(msg *Message) error {
+	data, err := format(w.Formatter, msg)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return net.ErrClosed
+	}
+
+	if w.conn == nil || (w.stream && !alive(w.conn)) {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+
+	err = w.write(data)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+
+	// syslogd may have been restarted.
+	if err := w.connect(); err != nil {
+		return err
+	}
+	return w.write(data)
+}
+
+func (w *UnixWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
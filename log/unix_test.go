@@ -0,0 +1,192 @@
+//go:build !windows && !plan9
+
+package log
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnixWriter_Write(t *testing.T) {
+	type test struct {
+		name   string
+		listen func(t *testing.T, path string) func() (string, error)
+		// replacer is applied by the framing of the socket.
+		replacer *strings.Replacer
+	}
+
+	listenDatagram := func(t *testing.T, path string) func() (string, error) {
+		conn, err := net.ListenPacket("unixgram", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return func() (string, error) {
+			buf := make([]byte, 1024)
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := conn.ReadFrom(buf)
+			return string(buf[:n]), err
+		}
+	}
+
+	listenStream := func(t *testing.T, path string) func() (string, error) {
+		ln, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { ln.Close() })
+		var r *bufio.Reader
+		return func() (string, error) {
+			if r == nil {
+				conn, err := ln.Accept()
+				if err != nil {
+					return "", err
+				}
+				t.Cleanup(func() { conn.Close() })
+				conn.SetReadDeadline(time.Now().Add(time.Second))
+				r = bufio.NewReader(conn)
+			}
+			return readNonTransparentFrame(r)
+		}
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "log")
+			read := tt.listen(t, path)
+
+			w, err := NewUnixWriter(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			for _, msg := range []string{"foo", "bar\nbaz"} {
+				if err := w.Write(newTestMessage(msg)); err != nil {
+					t.Fatal(err)
+				}
+				got, err := read()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := "<165>1 - - - - - - " + tt.replacer.Replace(msg); want != got {
+					t.Fatalf("want=%q, got=%q.", want, got)
+				}
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name:     "datagram",
+			listen:   listenDatagram,
+			replacer: strings.NewReplacer(),
+		},
+		{
+			name:     "fall back to stream",
+			listen:   listenStream,
+			replacer: strings.NewReplacer("\n", " "),
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestUnixWriter_reconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := NewUnixWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// syslogd restarts and recreates its socket.
+	conn.Close()
+	os.Remove(path)
+	conn, err = net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := w.Write(newTestMessage("after restart")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "<165>1 - - - - - - after restart", string(buf[:n]); want != got {
+		t.Fatalf("want=%v, got=%v.", want, got)
+	}
+}
+
+func TestNewUnixWriter_notFound(t *testing.T) {
+	_, err := NewUnixWriter(filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Fatalf("want-error=%v, error=%v.", true, err)
+	}
+}
+
+func TestUnixWriter_WriteTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the daemon accepts but never reads.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		<-stop
+	}()
+
+	w, err := NewUnixWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.WriteTimeout = 50 * time.Millisecond
+
+	msg := newTestMessage(strings.Repeat("a", 1<<20))
+	done := make(chan error)
+	go func() {
+		for {
+			if err := w.Write(msg); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("want-error=%v, error=%v.", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("want Write to time out")
+	}
+}
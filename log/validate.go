@@ -0,0 +1,271 @@
+package log
+
+import (
+	"context"
+	"github.com/a-skua/busybox-go/option"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RFC5424 header field and SD-NAME length limits in octets.
+const (
+	MaxHostNameSize  = 255
+	MaxAppNameSize   = 48
+	MaxProcessIDSize = 128
+	MaxMessageIDSize = 32
+	MaxSDNameSize    = 32
+)
+
+const (
+	maxFacility = FacilityLocalUse7
+	maxSeverity = SeverityDebug
+)
+
+// ValidationError reports a field violating the RFC 5424 constraints.
+type ValidationError struct {
+	Field string
+	Msg   string
+}
+
+func (err *ValidationError) Error() string {
+	return "log: invalid " + err.Field + ": " + err.Msg
+}
+
+func isPrintUSASCII(c byte) bool {
+	return c >= 33 && c <= 126
+}
+
+func isSDNameChar(c byte) bool {
+	return isPrintUSASCII(c) && c != '=' && c != ']' && c != '"'
+}
+
+// validateName checks a field of 1 to max characters satisfying valid.
+func validateName(field, name string, max int, valid func(byte) bool) error {
+	if name == "" {
+		return &ValidationError{Field: field, Msg: "empty"}
+	}
+	if len(name) > max {
+		return &ValidationError{Field: field, Msg: "longer than " + strconv.Itoa(max) + " octets"}
+	}
+	for i := 0; i < len(name); i++ {
+		if !valid(name[i]) {
+			return &ValidationError{Field: field, Msg: "invalid character " + strconv.QuoteRune(rune(name[i])) + " at " + strconv.Itoa(i)}
+		}
+	}
+	return nil
+}
+
+// sanitizeName replaces the invalid characters of name with '_' and truncates it to max octets.
+func sanitizeName(name string, max int, valid func(byte) bool) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !valid(c) {
+			b[i] = '_'
+		}
+	}
+	if len(b) > max {
+		b = b[:max]
+	}
+	return string(b)
+}
+
+func sanitizeOption[T ~string](v option.Option[T], max int) option.Option[T] {
+	if !v.Valid || v.Value == "" {
+		return option.None[T]()
+	}
+	return option.Some(T(sanitizeName(string(v.Value), max, isPrintUSASCII)))
+}
+
+func validateOption[T interface{ Validate() error }](v option.Option[T]) error {
+	if !v.Valid {
+		return nil
+	}
+	return v.Value.Validate()
+}
+
+func (pri Priority) Validate() error {
+	if pri.Facility > maxFacility {
+		return &ValidationError{Field: "facility", Msg: strconv.Itoa(int(pri.Facility)) + " is greater than " + strconv.Itoa(int(maxFacility))}
+	}
+	if pri.Severity > maxSeverity {
+		return &ValidationError{Field: "severity", Msg: strconv.Itoa(int(pri.Severity)) + " is greater than " + strconv.Itoa(int(maxSeverity))}
+	}
+	return nil
+}
+
+func (ver Version) Validate() error {
+	if ver < 1 {
+		return &ValidationError{Field: "version", Msg: "must be 1 or greater"}
+	}
+	return nil
+}
+
+func (host HostName) Validate() error {
+	return validateName("hostname", string(host), MaxHostNameSize, isPrintUSASCII)
+}
+
+func (app AppName) Validate() error {
+	return validateName("app-name", string(app), MaxAppNameSize, isPrintUSASCII)
+}
+
+func (proc ProcessID) Validate() error {
+	return validateName("procid", string(proc), MaxProcessIDSize, isPrintUSASCII)
+}
+
+func (msg MessageID) Validate() error {
+	return validateName("msgid", string(msg), MaxMessageIDSize, isPrintUSASCII)
+}
+
+func (id MetadataID) Validate() error {
+	return validateName("sd-id", string(id), MaxSDNameSize, isSDNameChar)
+}
+
+func (name MetadataName) Validate() error {
+	return validateName("param-name", string(name), MaxSDNameSize, isSDNameChar)
+}
+
+func (value MetadataValue) Validate() error {
+	if !utf8.ValidString(string(value)) {
+		return &ValidationError{Field: "param-value", Msg: "invalid UTF-8"}
+	}
+	return nil
+}
+
+func (h Header) Validate() error {
+	if err := h.Priority.Validate(); err != nil {
+		return err
+	}
+	if err := h.Version.Validate(); err != nil {
+		return err
+	}
+	if err := validateOption(h.Host); err != nil {
+		return err
+	}
+	if err := validateOption(h.App); err != nil {
+		return err
+	}
+	if err := validateOption(h.ProcessID); err != nil {
+		return err
+	}
+	return validateOption(h.MessageID)
+}
+
+func (param MetadataParam) Validate() error {
+	if err := param.Name.Validate(); err != nil {
+		return err
+	}
+	return param.Value.Validate()
+}
+
+func (meta Metadata) Validate() error {
+	if err := meta.ID.Validate(); err != nil {
+		return err
+	}
+	for _, param := range meta.Params {
+		if err := param.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks the Header and the Metadata, and that no SD-ID appears twice.
+func (msg *Message) Validate() error {
+	if err := msg.Header.Validate(); err != nil {
+		return err
+	}
+
+	seen := make(map[MetadataID]bool, len(msg.Metadata))
+	for _, meta := range msg.Metadata {
+		if err := meta.Validate(); err != nil {
+			return err
+		}
+		if seen[meta.ID] {
+			return &ValidationError{Field: "sd-id", Msg: strconv.Quote(string(meta.ID)) + " is duplicated"}
+		}
+		seen[meta.ID] = true
+	}
+	return nil
+}
+
+// sanitize returns a copy of msg satisfying Validate. Invalid characters are replaced
+// with '_', long fields are truncated and SD-ELEMENTs or params without a name are dropped.
+func sanitize(msg *Message) *Message {
+	head := msg.Header
+	if head.Priority.Facility > maxFacility {
+		head.Priority.Facility = FacilityUserLevelMessages
+	}
+	if head.Priority.Severity > maxSeverity {
+		head.Priority.Severity = maxSeverity
+	}
+	if head.Version < 1 {
+		head.Version = 1
+	}
+	head.Host = sanitizeOption(head.Host, MaxHostNameSize)
+	head.App = sanitizeOption(head.App, MaxAppNameSize)
+	head.ProcessID = sanitizeOption(head.ProcessID, MaxProcessIDSize)
+	head.MessageID = sanitizeOption(head.MessageID, MaxMessageIDSize)
+
+	metadata := make([]Metadata, 0, len(msg.Metadata))
+	for _, meta := range msg.Metadata {
+		if meta.ID == "" {
+			continue
+		}
+		params := make([]MetadataParam, 0, len(meta.Params))
+		for _, param := range meta.Params {
+			if param.Name == "" {
+				continue
+			}
+			params = append(params, NewMetadataParam(
+				MetadataName(sanitizeName(string(param.Name), MaxSDNameSize, isSDNameChar)),
+				MetadataValue(strings.ToValidUTF8(string(param.Value), "�")),
+			))
+		}
+		id := MetadataID(sanitizeName(string(meta.ID), MaxSDNameSize, isSDNameChar))
+		// sanitized SD-IDs may collide, which mergeMetadata resolves.
+		metadata = mergeMetadata(metadata, []Metadata{NewMetadata(id, params...)})
+	}
+
+	return NewMessage(head, metadata, msg.Message...)
+}
+
+type ValidationMode uint8
+
+const (
+	// ValidationReject returns the ValidationError without writing the Message.
+	ValidationReject ValidationMode = iota
+	// ValidationSanitize writes a sanitized copy of an invalid Message.
+	ValidationSanitize
+)
+
+// ValidatingWriter checks Messages against RFC 5424 before passing them to another Writer.
+type ValidatingWriter struct {
+	writer Writer
+	mode   ValidationMode
+}
+
+func NewValidatingWriter(w Writer, mode ValidationMode) *ValidatingWriter {
+	return &ValidatingWriter{
+		writer: w,
+		mode:   mode,
+	}
+}
+
+func (w *ValidatingWriter) Write(msg *Message) error {
+	if err := msg.Validate(); err != nil {
+		if w.mode == ValidationReject {
+			return err
+		}
+		msg = sanitize(msg)
+	}
+	return w.writer.Write(msg)
+}
+
+func (w *ValidatingWriter) Flush(ctx context.Context) error {
+	return FlushWriter(ctx, w.writer)
+}
+
+func (w *ValidatingWriter) Close(ctx context.Context) error {
+	return CloseWriter(ctx, w.writer)
+}
@@ -0,0 +1,201 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"github.com/a-skua/busybox-go/option"
+	"strings"
+	"testing"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	type test struct {
+		name      string
+		modify    func(*Message)
+		wantField string
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newTestMessage("foo")
+			tt.modify(msg)
+
+			err := msg.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Field != tt.wantField {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantField, err)
+			}
+		})
+	}
+
+	tests := []*test{
+		{
+			name: "valid",
+			modify: func(msg *Message) {
+				msg.Header.Host = option.Some(HostName(strings.Repeat("h", MaxHostNameSize)))
+				msg.Header.App = option.Some(AppName(strings.Repeat("a", MaxAppNameSize)))
+				msg.Header.ProcessID = option.Some(ProcessID(strings.Repeat("p", MaxProcessIDSize)))
+				msg.Header.MessageID = option.Some(MessageID(strings.Repeat("m", MaxMessageIDSize)))
+				msg.Metadata = []Metadata{NewMetadata("exampleSDID@32473", NewMetadataParam("iut", "3"))}
+			},
+		},
+		{
+			name:      "facility",
+			modify:    func(msg *Message) { msg.Header.Priority.Facility = 24 },
+			wantField: "facility",
+		},
+		{
+			name:      "severity",
+			modify:    func(msg *Message) { msg.Header.Priority.Severity = 8 },
+			wantField: "severity",
+		},
+		{
+			name:      "version",
+			modify:    func(msg *Message) { msg.Header.Version = 0 },
+			wantField: "version",
+		},
+		{
+			name:      "hostname too long",
+			modify:    func(msg *Message) { msg.Header.Host = option.Some(HostName(strings.Repeat("h", MaxHostNameSize+1))) },
+			wantField: "hostname",
+		},
+		{
+			name:      "app-name with space",
+			modify:    func(msg *Message) { msg.Header.App = option.Some(AppName("my app")) },
+			wantField: "app-name",
+		},
+		{
+			name:      "empty procid",
+			modify:    func(msg *Message) { msg.Header.ProcessID = option.Some(ProcessID("")) },
+			wantField: "procid",
+		},
+		{
+			name: "msgid too long",
+			modify: func(msg *Message) {
+				msg.Header.MessageID = option.Some(MessageID(strings.Repeat("m", MaxMessageIDSize+1)))
+			},
+			wantField: "msgid",
+		},
+		{
+			name:      "non-ascii msgid",
+			modify:    func(msg *Message) { msg.Header.MessageID = option.Some(MessageID("ログ")) },
+			wantField: "msgid",
+		},
+		{
+			name:      "sd-id with '='",
+			modify:    func(msg *Message) { msg.Metadata = []Metadata{NewMetadata("a=b")} },
+			wantField: "sd-id",
+		},
+		{
+			name:      "param-name with '\"'",
+			modify:    func(msg *Message) { msg.Metadata = []Metadata{NewMetadata("id", NewMetadataParam(`a"b`, "c"))} },
+			wantField: "param-name",
+		},
+		{
+			name:      "param-value",
+			modify:    func(msg *Message) { msg.Metadata = []Metadata{NewMetadata("id", NewMetadataParam("a", "\xff"))} },
+			wantField: "param-value",
+		},
+		{
+			name:      "duplicated sd-id",
+			modify:    func(msg *Message) { msg.Metadata = []Metadata{NewMetadata("id"), NewMetadata("id")} },
+			wantField: "sd-id",
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}
+
+func TestValidatingWriter_Write(t *testing.T) {
+	type test struct {
+		name    string
+		mode    ValidationMode
+		message *Message
+		want    string
+		wantErr bool
+	}
+
+	do := func(tt *test) {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &lifecycleWriter{}
+			v := NewValidatingWriter(w, tt.mode)
+			err := v.Write(tt.message)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("want-error=%v, error=%v.", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				if len(w.messages) != 0 {
+					t.Fatalf("want=%v, got=%v.", 0, len(w.messages))
+				}
+				return
+			}
+			if got := w.messages[0].String(); tt.want != got {
+				t.Fatalf("want=%v, got=%v.", tt.want, got)
+			}
+			if err := w.messages[0].Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := v.Flush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if err := v.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if w.flushed != 1 || w.closed != 1 {
+				t.Fatalf("want=%v, got=%v.", [2]int{1, 1}, [2]int{w.flushed, w.closed})
+			}
+		})
+	}
+
+	invalid := NewMessage(
+		NewHeader(
+			NewPriority(30, SeverityNotice),
+			0,
+			option.None[Timestamp](),
+			option.Some(HostName("my host")),
+			option.Some(AppName(strings.Repeat("a", 50))),
+			option.Some(ProcessID("")),
+			option.Some(MessageID("ID 47")),
+		),
+		[]Metadata{
+			NewMetadata("a=b", NewMetadataParam("x y", "1"), NewMetadataParam("", "dropped")),
+			NewMetadata("a b", NewMetadataParam("z", "\xff")),
+			NewMetadata(""),
+		},
+		"foo",
+	)
+
+	tests := []*test{
+		{
+			name:    "valid",
+			mode:    ValidationReject,
+			message: newTestMessage("foo"),
+			want:    "<165>1 - - - - - - foo",
+		},
+		{
+			name:    "reject",
+			mode:    ValidationReject,
+			message: invalid,
+			wantErr: true,
+		},
+		{
+			name:    "sanitize",
+			mode:    ValidationSanitize,
+			message: invalid,
+			want:    "<13>1 - my_host " + strings.Repeat("a", MaxAppNameSize) + ` - ID_47 [a_b x_y="1" z="�"] foo`,
+		},
+	}
+
+	for _, tt := range tests {
+		do(tt)
+	}
+}